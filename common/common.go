@@ -12,7 +12,9 @@ package common
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"time"
 
 	logRotate "github.com/lestrrat-go/file-rotatelogs"
 	"github.com/pkg/errors"
@@ -22,6 +24,8 @@ import (
 )
 
 type OptLog struct {
+	//logger name such as user/gin/gorm, used as label in metrics
+	LoggerName string
 
 	//time format for screen log
 	StdoutTimeFormat string
@@ -45,6 +49,24 @@ type OptLog struct {
 	ErrLogPrefix string
 	//error log suffix "%Y%m" if SetErrFileHook is true
 	ErrLogSuffix string
+	//alternate path for logs if writing to LogPath failed, disabled if empty
+	FallbackPath string
+	//write to stderr if all log files failed
+	FallbackStderr bool
+	//retry LogPath after this interval once writing failed
+	RetryInterval time.Duration
+
+	//count entries and bytes written if not nil
+	Metrics *Metrics
+	//extra hooks such as OTLP exporter
+	Hooks []logrus.Hook
+	//formatter for log file such as access.log, error.log and stdout are not affected
+	FileFormatter logrus.Formatter
+
+	//writers created by ConfigLogrus
+	writers []*FallbackWriter
+	//share writers with other loggers if not nil
+	pool *WriterPool
 }
 
 // SetStdoutTimeFormat sets the stdout time format.
@@ -118,6 +140,102 @@ func (o *OptLog) SetErrLogSuffix(suffix string) error {
 	return nil
 }
 
+// SetFallbackPath sets the alternate path when writing to LogPath failed.
+func (o *OptLog) SetFallbackPath(path string) error {
+	o.FallbackPath = path
+	return nil
+}
+
+// SetFallbackStderr sets whether to write to stderr when all log files failed.
+func (o *OptLog) SetFallbackStderr(enabled bool) error {
+	o.FallbackStderr = enabled
+	return nil
+}
+
+// SetRetryInterval sets the interval to retry LogPath after writing failed.
+func (o *OptLog) SetRetryInterval(interval time.Duration) error {
+	if interval < 0 {
+		return errors.New("retry interval cannot be negative")
+	}
+	o.RetryInterval = interval
+	return nil
+}
+
+// SetLoggerName sets the logger name used in metrics.
+func (o *OptLog) SetLoggerName(name string) error {
+	if name == "" {
+		return errors.New("logger name cannot be empty")
+	}
+	o.LoggerName = name
+	return nil
+}
+
+// SetMetrics sets metrics to count log entries and bytes written.
+func (o *OptLog) SetMetrics(m *Metrics) error {
+	o.Metrics = m
+	return nil
+}
+
+// SetHooks sets extra hooks added to logger.
+func (o *OptLog) SetHooks(hooks ...logrus.Hook) error {
+	o.Hooks = append(o.Hooks, hooks...)
+	return nil
+}
+
+// SetFileFormatter sets formatter for log file, nil to use the default text format.
+func (o *OptLog) SetFileFormatter(f logrus.Formatter) error {
+	o.FileFormatter = f
+	return nil
+}
+
+// SetWriterPool sets pool to share log file writers with other loggers.
+func (o *OptLog) SetWriterPool(pool *WriterPool) error {
+	o.pool = pool
+	return nil
+}
+
+// WriteErrors returns count of failed writes for all log files.
+func (o *OptLog) WriteErrors() uint64 {
+	var count uint64
+	for _, w := range o.writers {
+		count += w.WriteErrors()
+	}
+	return count
+}
+
+// LastWriteError returns the most recent write error of all log files, nil if never failed.
+func (o *OptLog) LastWriteError() error {
+	var (
+		last   error
+		lastAt time.Time
+	)
+	for _, w := range o.writers {
+		if at, err := w.lastError(); err != nil && (last == nil || at.After(lastAt)) {
+			last, lastAt = err, at
+		}
+	}
+	return last
+}
+
+// Close closes log files created by ConfigLogrus, shared writers are closed by WriterPool.
+func (o *OptLog) Close() error {
+	if o.pool != nil {
+		return nil
+	}
+	var err error
+	for _, w := range o.writers {
+		if e := w.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// Writers returns the log file writers created by ConfigLogrus.
+func (o *OptLog) Writers() []*FallbackWriter {
+	return o.writers
+}
+
 /*InitOpt
  * @msg init logrus params
  * @return: *OptLog
@@ -126,6 +244,7 @@ func InitOpt() *OptLog {
 	return &OptLog{
 		StdoutTimeFormat:  "06/01/02 15:04:05",
 		LogFileTimeFormat: "2006-01-02 15:04:05.000000",
+		LoggerName:        "log",
 		//path for all logs
 		LogPath:        "./logs/",
 		FileNamePrefix: "log", //log in access.log.20230105
@@ -138,6 +257,9 @@ func InitOpt() *OptLog {
 		LogLevel:     logrus.DebugLevel,
 		ErrLogPrefix: "error.log",
 		ErrLogSuffix: "%Y%m",
+		//stderr is the last resort if log file is not writable
+		FallbackStderr: true,
+		RetryInterval:  10 * time.Second,
 	}
 }
 
@@ -191,6 +313,14 @@ func (opt *OptLog) ConfigLogrus() (*logrus.Logger, error) {
 		DisableColors:   false,
 	}
 	log.SetFormatter(stdoutFmt)
+	//hooks fire in order, extra hooks such as ContextHook add fields before file hooks write
+	for _, h := range opt.Hooks {
+		log.AddHook(h)
+	}
+	if opt.Metrics != nil {
+		//count entries by logger name and level
+		log.AddHook(opt.Metrics.Hook(opt.LoggerName))
+	}
 
 	logFileFmt := &logFmt.TextFormatter{
 		FullTimestamp:   true,
@@ -203,37 +333,29 @@ func (opt *OptLog) ConfigLogrus() (*logrus.Logger, error) {
 	if err := os.MkdirAll(opt.LogPath, 0775); err != nil {
 		return log, errors.Cause(err)
 	}
-	FileNamePrefix := fmt.Sprintf("%s%s", opt.LogPath, opt.FileNamePrefix)
-	logWriter, err := logRotate.New(fmt.Sprintf("%v.%v", FileNamePrefix, opt.FileNameSuffixTimeFormat),
-		logRotate.WithLinkName(FileNamePrefix),           //create log link, such as ln -s access.log.20230205 access.log
-		logRotate.WithMaxAge(-1),                         //disable remove log by create time
-		logRotate.WithRotationCount(uint(opt.KeepCount)), //set count for keeping log
-	)
+	logWriter, err := opt.newWriter(opt.FileNamePrefix, opt.FileNameSuffixTimeFormat)
 	if err != nil {
 		return log, errors.Cause(err)
 	}
 
+	var fileFmt logrus.Formatter = logFileFmt
+	if opt.FileFormatter != nil {
+		fileFmt = opt.FileFormatter
+	}
 	logHook := fileLogHook.NewHook(fileLogHook.WriterMap{
 		logrus.DebugLevel: logWriter,
 		logrus.InfoLevel:  logWriter,
 		logrus.WarnLevel:  logWriter,
 		logrus.ErrorLevel: logWriter,
 		logrus.FatalLevel: logWriter,
-	}, logFileFmt)
+	}, fileFmt)
 	//opt.MapLogFile[opt.FileNamePrefix] = fmt.Sprintf("%s.%s", FileNamePrefix, opt.FileNameSuffixTimeFormat)
 	//writing log to file when printing to screen by hook
 	log.AddHook(logHook)
 	fmt.Println(logWriter.CurrentFileName())
 	if opt.SetErrFileHook {
-		//full path for log filename
-		FileNamePrefix = fmt.Sprintf("%s%s", opt.LogPath, opt.ErrLogPrefix)
 		//errWriter for error log such as ./logs/error.log.202301
-		errWriter, err := logRotate.New(fmt.Sprintf("%v.%v", FileNamePrefix, opt.ErrLogSuffix),
-			logRotate.WithLinkName(FileNamePrefix),           // such as ln -s error.log.202301 error.log
-			logRotate.WithMaxAge(-1),                         //disable keep log by created time
-			logRotate.WithRotationCount(uint(opt.KeepCount)), //keep alive log by count
-		)
-
+		errWriter, err := opt.newWriter(opt.ErrLogPrefix, opt.ErrLogSuffix)
 		if err != nil {
 			return log, errors.Cause(err)
 		}
@@ -251,3 +373,63 @@ func (opt *OptLog) ConfigLogrus() (*logrus.Logger, error) {
 	}
 	return log, nil
 }
+
+/*newWriter
+ * @msg create rotated log writer under LogPath, and FallbackPath/stderr
+ *		as fallback sinks. it's shared by loggers with same WriterPool
+ * @receiver opt
+ * @param prefix such as access.log
+ * @param suffix such as %Y%m%d
+ * @return: *FallbackWriter
+ * @return: error
+ */
+func (opt *OptLog) newWriter(prefix, suffix string) (*FallbackWriter, error) {
+	if opt.pool == nil {
+		return opt.createWriter(prefix, suffix)
+	}
+	w, err := opt.pool.get(fmt.Sprintf("%s%s.%s", opt.LogPath, prefix, suffix), func() (*FallbackWriter, error) {
+		return opt.createWriter(prefix, suffix)
+	})
+	if err != nil {
+		return nil, err
+	}
+	opt.writers = append(opt.writers, w)
+	return w, nil
+}
+
+// createWriter creates writer which is not shared
+func (opt *OptLog) createWriter(prefix, suffix string) (*FallbackWriter, error) {
+	primary, err := newRotateLog(opt.LogPath, prefix, suffix, opt.KeepCount)
+	if err != nil {
+		return nil, err
+	}
+	var fallback []io.Writer
+	if opt.FallbackPath != "" {
+		//dir is created by rotatelogs when writing
+		alt, err := newRotateLog(opt.FallbackPath, prefix, suffix, opt.KeepCount)
+		if err != nil {
+			return nil, err
+		}
+		fallback = append(fallback, alt)
+	}
+	if opt.FallbackStderr {
+		fallback = append(fallback, os.Stderr)
+	}
+	w := NewFallbackWriter(fmt.Sprintf("%s%s", opt.LogPath, prefix), opt.RetryInterval,
+		primary, fallback...)
+	w.metrics = opt.Metrics
+	if opt.pool == nil {
+		opt.writers = append(opt.writers, w)
+	}
+	return w, nil
+}
+
+// newRotateLog creates rotated log such as path/access.log.20230105 with link path/access.log
+func newRotateLog(path, prefix, suffix string, keepCount int) (*logRotate.RotateLogs, error) {
+	FileNamePrefix := fmt.Sprintf("%s%s", path, prefix)
+	return logRotate.New(fmt.Sprintf("%v.%v", FileNamePrefix, suffix),
+		logRotate.WithLinkName(FileNamePrefix),       //create log link, such as ln -s access.log.20230205 access.log
+		logRotate.WithMaxAge(-1),                     //disable remove log by create time
+		logRotate.WithRotationCount(uint(keepCount)), //set count for keeping log
+	)
+}
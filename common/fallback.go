@@ -0,0 +1,186 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 09:12:40
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 09:12:40
+ * @FilePath: /xlogrus/common/fallback.go
+ * @Description: fallback chain for log file writers
+ *
+ */
+
+package common
+
+import (
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+/*FallbackWriter
+ * @msg write to the primary sink first, then alternate path, then stderr.
+ *		once the primary failed, it's retried after RetryInterval so the
+ *		writer recovers automatically when disk space/permission comes back
+ */
+type FallbackWriter struct {
+	//name for this writer, such as ./logs/access.log
+	Name string
+	//sinks in order: primary, alternate path, stderr
+	sinks []io.Writer
+	//retry primary after this interval once it failed
+	retryInterval time.Duration
+	//count bytes and errors if not nil
+	metrics *Metrics
+
+	mu      sync.Mutex
+	active  int       //index of the sink in use
+	retryAt time.Time //next time to retry the primary
+	lastErr error
+	errAt   time.Time //time of lastErr
+
+	errCount atomic.Uint64
+}
+
+// NewFallbackWriter creates writer with primary sink and optional fallback sinks.
+func NewFallbackWriter(name string, retryInterval time.Duration, primary io.Writer,
+	fallback ...io.Writer) *FallbackWriter {
+	sinks := []io.Writer{primary}
+	for _, w := range fallback {
+		if w != nil {
+			sinks = append(sinks, w)
+		}
+	}
+	return &FallbackWriter{
+		Name:          name,
+		sinks:         sinks,
+		retryInterval: retryInterval,
+	}
+}
+
+// Write implements io.Writer, error is only returned if all sinks failed.
+func (w *FallbackWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	start := w.active
+	//primary is back, try it again
+	if start > 0 && !time.Now().Before(w.retryAt) {
+		start = 0
+	}
+	var err error
+	for i := start; i < len(w.sinks); i++ {
+		var n int
+		if n, err = w.sinks[i].Write(p); err == nil {
+			w.active = i
+			if w.metrics != nil {
+				w.metrics.AddBytes(w.Name, n)
+			}
+			return n, nil
+		}
+		w.errCount.Add(1)
+		if w.metrics != nil {
+			w.metrics.AddWriteError(w.Name)
+		}
+		w.lastErr, w.errAt = errors.Wrapf(err, "failed to write %s", w.Name), time.Now()
+		if i == 0 {
+			//wait before retrying primary
+			w.retryAt = time.Now().Add(w.retryInterval)
+		}
+	}
+	return 0, w.lastErr
+}
+
+// CurrentFileName returns file name of the primary sink if it's rotated log.
+func (w *FallbackWriter) CurrentFileName() string {
+	if rl, ok := w.sinks[0].(interface{ CurrentFileName() string }); ok {
+		return rl.CurrentFileName()
+	}
+	return ""
+}
+
+// Active returns index of the sink in use, 0 is primary.
+func (w *FallbackWriter) Active() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.active
+}
+
+// WriteErrors returns count of failed writes for all sinks.
+func (w *FallbackWriter) WriteErrors() uint64 {
+	return w.errCount.Load()
+}
+
+// LastError returns the latest write error, nil if never failed.
+func (w *FallbackWriter) LastError() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.lastErr
+}
+
+// lastError returns time of the latest write error and the error
+func (w *FallbackWriter) lastError() (time.Time, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.errAt, w.lastErr
+}
+
+// Close closes all sinks which implement io.Closer except stdout/stderr.
+func (w *FallbackWriter) Close() error {
+	var err error
+	for _, s := range w.sinks {
+		if s == os.Stderr || s == os.Stdout {
+			continue
+		}
+		if cl, ok := s.(io.Closer); ok {
+			if e := cl.Close(); e != nil && err == nil {
+				err = e
+			}
+		}
+	}
+	return err
+}
+
+/*WriterPool
+ * @msg share writers with same file pattern between loggers, such as
+ *		error.log for user/gin/gorm logs
+ */
+type WriterPool struct {
+	mu      sync.Mutex
+	writers map[string]*FallbackWriter
+}
+
+func NewWriterPool() *WriterPool {
+	return &WriterPool{writers: make(map[string]*FallbackWriter)}
+}
+
+// get returns writer for key, it's created by fn if not found
+func (p *WriterPool) get(key string, fn func() (*FallbackWriter, error)) (*FallbackWriter, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if w, ok := p.writers[key]; ok {
+		return w, nil
+	}
+	w, err := fn()
+	if err != nil {
+		return nil, err
+	}
+	p.writers[key] = w
+	return w, nil
+}
+
+// Close closes all writers in pool.
+func (p *WriterPool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var err error
+	for key, w := range p.writers {
+		if e := w.Close(); e != nil && err == nil {
+			err = e
+		}
+		delete(p.writers, key)
+	}
+	return err
+}
@@ -0,0 +1,146 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 10:05:31
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 10:05:31
+ * @FilePath: /xlogrus/common/metrics.go
+ * @Description: log volume metrics in prometheus text format
+ *
+ */
+
+package common
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+type entryKey struct {
+	logger string
+	level  string
+}
+
+/*Metrics
+ * @msg count log entries by logger name and level, and bytes written per file.
+ *		it's served as prometheus text exposition format by ServeHTTP
+ */
+type Metrics struct {
+	mu          sync.Mutex
+	entries     map[entryKey]uint64
+	bytes       map[string]uint64
+	writeErrors map[string]uint64
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		entries:     make(map[entryKey]uint64),
+		bytes:       make(map[string]uint64),
+		writeErrors: make(map[string]uint64),
+	}
+}
+
+// Hook returns logrus hook to count entries for logger name such as user/gin/gorm
+func (m *Metrics) Hook(logger string) logrus.Hook {
+	return &metricsHook{m, logger}
+}
+
+// AddBytes adds bytes written to file.
+func (m *Metrics) AddBytes(file string, n int) {
+	m.mu.Lock()
+	m.bytes[file] += uint64(n)
+	m.mu.Unlock()
+}
+
+// AddWriteError adds failed write to file.
+func (m *Metrics) AddWriteError(file string) {
+	m.mu.Lock()
+	m.writeErrors[file]++
+	m.mu.Unlock()
+}
+
+// Entries returns count of entries for logger and level.
+func (m *Metrics) Entries(logger string, level logrus.Level) uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.entries[entryKey{logger, level.String()}]
+}
+
+// Bytes returns bytes written to file.
+func (m *Metrics) Bytes(file string) uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.bytes[file]
+}
+
+// ServeHTTP implements http.Handler with prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// WriteTo writes all metrics in prometheus text exposition format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	var b strings.Builder
+	b.WriteString("# HELP xlogrus_log_entries_total Number of log entries by logger and level.\n")
+	b.WriteString("# TYPE xlogrus_log_entries_total counter\n")
+	keys := make([]entryKey, 0, len(m.entries))
+	for k := range m.entries {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].logger != keys[j].logger {
+			return keys[i].logger < keys[j].logger
+		}
+		return keys[i].level < keys[j].level
+	})
+	for _, k := range keys {
+		fmt.Fprintf(&b, "xlogrus_log_entries_total{logger=\"%s\",level=\"%s\"} %d\n",
+			escapeLabel(k.logger), escapeLabel(k.level), m.entries[k])
+	}
+	writeFileCounter(&b, "xlogrus_log_bytes_total", "Bytes written to log file.", m.bytes)
+	writeFileCounter(&b, "xlogrus_log_write_errors_total", "Failed writes to log file.", m.writeErrors)
+	m.mu.Unlock()
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func writeFileCounter(b *strings.Builder, name, help string, values map[string]uint64) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	files := make([]string, 0, len(values))
+	for f := range values {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	for _, f := range files {
+		fmt.Fprintf(b, "%s{file=\"%s\"} %d\n", name, escapeLabel(f), values[f])
+	}
+}
+
+// escapeLabel escapes label value with backslash, double-quote and line feed
+func escapeLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+type metricsHook struct {
+	m      *Metrics
+	logger string
+}
+
+func (h *metricsHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *metricsHook) Fire(entry *logrus.Entry) error {
+	h.m.mu.Lock()
+	h.m.entries[entryKey{h.logger, entry.Level.String()}]++
+	h.m.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,270 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 16:45:10
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 16:45:10
+ * @FilePath: /xlogrus/component.go
+ * @Description: named component loggers with per-component level
+ *
+ */
+
+package xlogrus
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+/*Components
+ * @msg registry of component levels for one logger.
+ *		level spec such as "*=info,payment=debug,payment.stripe=trace",
+ *		level of "payment.stripe.refund" is inherited from "payment.stripe",
+ *		then "payment", then "*"
+ */
+type Components struct {
+	lg *TLogrus
+	//logger of components, entries are filtered by component level instead of level of lg
+	out *TLogrus
+
+	mu     sync.RWMutex
+	levels map[string]logrus.Level
+}
+
+/*NewComponents
+ * @msg create component registry for lg, level of lg is not changed,
+ *		components log with output, formatter and hooks of lg when it's created,
+ *		so SetOutput, SetFormatter and AddHook of lg should be called before it
+ * @param lg logger from NewUserLog/NewGinLog
+ * @param spec level spec, level of lg is used for components not in spec
+ * @return: *Components
+ * @return: error
+ */
+func NewComponents(lg *TLogrus, spec string) (*Components, error) {
+	if lg == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	cs := &Components{lg: lg, out: componentLogger(lg)}
+	if err := cs.SetLevels(spec); err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
+// ParseLevelSpec parses spec such as "*=info,payment=debug", "debug" is same as "*=debug"
+func ParseLevelSpec(spec string) (map[string]logrus.Level, error) {
+	levels := make(map[string]logrus.Level)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, lvlStr := "*", item
+		if i := strings.IndexByte(item, '='); i >= 0 {
+			name, lvlStr = strings.TrimSpace(item[:i]), strings.TrimSpace(item[i+1:])
+		}
+		if name == "" {
+			return nil, errors.Errorf("empty component name in %q", item)
+		}
+		lvl, err := logrus.ParseLevel(lvlStr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid level for component %s", name)
+		}
+		levels[name] = lvl
+	}
+	return levels, nil
+}
+
+// SetLevels replaces component levels at runtime.
+func (cs *Components) SetLevels(spec string) error {
+	levels, err := ParseLevelSpec(spec)
+	if err != nil {
+		return err
+	}
+	cs.mu.Lock()
+	cs.levels = levels
+	cs.mu.Unlock()
+	return nil
+}
+
+// SetLevel sets level for one component at runtime, "*" for default.
+func (cs *Components) SetLevel(name string, level logrus.Level) {
+	cs.mu.Lock()
+	cs.levels[name] = level
+	cs.mu.Unlock()
+}
+
+// Spec returns current level spec sorted by component name.
+func (cs *Components) Spec() string {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	names := make([]string, 0, len(cs.levels))
+	for name := range cs.levels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	items := make([]string, 0, len(names))
+	for _, name := range names {
+		items = append(items, fmt.Sprintf("%s=%s", name, cs.levels[name]))
+	}
+	return strings.Join(items, ",")
+}
+
+// Level returns level for component, it's inherited from parent component if not set,
+// then "*", then level of logger.
+func (cs *Components) Level(name string) logrus.Level {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	for {
+		if lvl, ok := cs.levels[name]; ok {
+			return lvl
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	if lvl, ok := cs.levels["*"]; ok {
+		return lvl
+	}
+	return cs.lg.GetLevel()
+}
+
+// Named returns component logger with field component=name.
+func (cs *Components) Named(name string) *Component {
+	return &Component{
+		cs:    cs,
+		name:  name,
+		entry: cs.out.WithField("component", name),
+	}
+}
+
+/*componentLogger
+ * @msg logger sharing output, formatter and hooks with lg, its level is trace
+ *		so that entries are only filtered by component level, logrus checks level
+ *		of logger before hooks and output. output of lg is replaced by a locked
+ *		writer shared by both loggers, hooks are copied as map of lg is guarded
+ *		by its own lock
+ * @param lg
+ * @return: *TLogrus
+ */
+func componentLogger(lg *TLogrus) *TLogrus {
+	out, ok := lg.Out.(*lockedWriter)
+	if !ok {
+		out = &lockedWriter{w: lg.Out}
+		lg.SetOutput(out)
+	}
+	hooks := make(logrus.LevelHooks, len(lg.Hooks))
+	for level, hs := range lg.Hooks {
+		hooks[level] = append([]logrus.Hook(nil), hs...)
+	}
+	return &logrus.Logger{
+		Out:          out,
+		Hooks:        hooks,
+		Formatter:    lg.Formatter,
+		ReportCaller: lg.ReportCaller,
+		Level:        logrus.TraceLevel,
+		ExitFunc:     lg.ExitFunc,
+	}
+}
+
+// lockedWriter serializes writes of loggers sharing the same output
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
+
+// Component logs with its own level, created by Components.Named
+type Component struct {
+	cs    *Components
+	name  string
+	entry *logrus.Entry
+}
+
+// Name returns full name such as payment.stripe
+func (l *Component) Name() string {
+	return l.name
+}
+
+// Named returns child component such as payment.stripe for payment.
+func (l *Component) Named(name string) *Component {
+	full := l.name + "." + name
+	return &Component{cs: l.cs, name: full, entry: l.entry.WithField("component", full)}
+}
+
+// Entry returns logrus entry with component field, it's not filtered by component level.
+func (l *Component) Entry() *logrus.Entry {
+	return l.entry
+}
+
+func (l *Component) with(entry *logrus.Entry) *Component {
+	return &Component{cs: l.cs, name: l.name, entry: entry}
+}
+
+func (l *Component) WithField(key string, value interface{}) *Component {
+	return l.with(l.entry.WithField(key, value))
+}
+
+func (l *Component) WithFields(fields logrus.Fields) *Component {
+	return l.with(l.entry.WithFields(fields))
+}
+
+func (l *Component) WithError(err error) *Component {
+	return l.with(l.entry.WithError(err))
+}
+
+func (l *Component) WithContext(ctx context.Context) *Component {
+	return l.with(l.entry.WithContext(ctx))
+}
+
+// IsLevelEnabled checks level against component level.
+func (l *Component) IsLevelEnabled(level logrus.Level) bool {
+	return l.cs.Level(l.name) >= level
+}
+
+func (l *Component) Log(level logrus.Level, args ...interface{}) {
+	if l.IsLevelEnabled(level) {
+		l.entry.Log(level, args...)
+	}
+}
+
+func (l *Component) Logf(level logrus.Level, format string, args ...interface{}) {
+	if l.IsLevelEnabled(level) {
+		l.entry.Logf(level, format, args...)
+	}
+}
+
+func (l *Component) Trace(args ...interface{}) { l.Log(logrus.TraceLevel, args...) }
+func (l *Component) Debug(args ...interface{}) { l.Log(logrus.DebugLevel, args...) }
+func (l *Component) Info(args ...interface{})  { l.Log(logrus.InfoLevel, args...) }
+func (l *Component) Warn(args ...interface{})  { l.Log(logrus.WarnLevel, args...) }
+func (l *Component) Error(args ...interface{}) { l.Log(logrus.ErrorLevel, args...) }
+
+func (l *Component) Tracef(format string, args ...interface{}) {
+	l.Logf(logrus.TraceLevel, format, args...)
+}
+func (l *Component) Debugf(format string, args ...interface{}) {
+	l.Logf(logrus.DebugLevel, format, args...)
+}
+func (l *Component) Infof(format string, args ...interface{}) {
+	l.Logf(logrus.InfoLevel, format, args...)
+}
+func (l *Component) Warnf(format string, args ...interface{}) {
+	l.Logf(logrus.WarnLevel, format, args...)
+}
+func (l *Component) Errorf(format string, args ...interface{}) {
+	l.Logf(logrus.ErrorLevel, format, args...)
+}
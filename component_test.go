@@ -0,0 +1,141 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 17:12:26
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 17:12:26
+ * @FilePath: /xlogrus/component_test.go
+ * @Description: test component loggers with level spec
+ *
+ */
+
+package xlogrus
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	lTest "github.com/sirupsen/logrus/hooks/test" //logrus tools for test
+	req "github.com/stretchr/testify/require"     //exit if failed
+)
+
+func TestComponents(t *testing.T) {
+	lg, opt, err := NewUserLog(WithLogPath[UserOpt](t.TempDir() + "/"))
+	req.NoError(t, err)
+	defer opt.Close()
+	lg.SetOutput(io.Discard)
+	hook := lTest.NewLocal(lg)
+
+	_, err = NewComponents(lg, "*=info,payment=verbose")
+	req.Error(t, err)
+
+	cs, err := NewComponents(lg, "*=info,payment=debug,payment.stripe=trace")
+	req.NoError(t, err)
+	req.Equal(t, logrus.DebugLevel, lg.GetLevel())
+	req.Equal(t, "*=info,payment=debug,payment.stripe=trace", cs.Spec())
+
+	payment := cs.Named("payment")
+	stripe := payment.Named("stripe")
+	tests := []struct {
+		name   string
+		log    func()
+		isWant bool
+	}{
+		{"defaultInfo", func() { cs.Named("order").Info("order info") }, true},
+		{"defaultDebug", func() { cs.Named("order").Debug("order debug") }, false},
+		{"payment", func() { payment.Debug("payment debug") }, true},
+		{"paymentTrace", func() { payment.Trace("payment trace") }, false},
+		{"inherited", func() { stripe.Named("refund").Trace("refund trace") }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook.Reset()
+			tt.log()
+			if tt.isWant {
+				req.NotNil(t, hook.LastEntry())
+			} else {
+				req.Nil(t, hook.LastEntry())
+			}
+		})
+	}
+
+	stripe.WithField("amount", 10).Trace("charge")
+	req.Equal(t, "payment.stripe", hook.LastEntry().Data["component"])
+	req.Equal(t, 10, hook.LastEntry().Data["amount"])
+
+	//change at runtime
+	req.NoError(t, cs.SetLevels("warn"))
+	req.Equal(t, logrus.WarnLevel, cs.Level("payment.stripe"))
+	hook.Reset()
+	payment.Info("dropped")
+	req.Nil(t, hook.LastEntry())
+	cs.SetLevel("payment", logrus.InfoLevel)
+	payment.Info("kept")
+	req.Equal(t, "kept", hook.LastEntry().Message)
+}
+
+func TestComponentsLevel(t *testing.T) {
+	lg, opt, err := NewUserLog(WithLogPath[UserOpt](t.TempDir()+"/"), WithLogLevel[UserOpt]("info"))
+	req.NoError(t, err)
+	defer opt.Close()
+	lg.SetOutput(io.Discard)
+	hook := lTest.NewLocal(lg)
+
+	cs, err := NewComponents(lg, "payment=trace,order=warn")
+	req.NoError(t, err)
+	//level of shared logger is not raised
+	req.Equal(t, logrus.InfoLevel, lg.GetLevel())
+	lg.Debug("direct debug")
+	req.Empty(t, hook.AllEntries())
+
+	payment, order := cs.Named("payment"), cs.Named("order")
+	payment.Trace("payment trace")
+	payment.Debug("payment debug")
+	order.Info("order info")
+	cs.Named("user").Debug("user debug")
+	cs.Named("user").Info("user info")
+	var msgs []string
+	for _, e := range hook.AllEntries() {
+		msgs = append(msgs, e.Message)
+	}
+	req.Equal(t, []string{"payment trace", "payment debug", "user info"}, msgs)
+
+	//file hooks of lg write entries of components
+	content, err := os.ReadFile(opt.Writers()[0].CurrentFileName())
+	req.NoError(t, err)
+	req.Contains(t, string(content), "payment debug")
+	req.NotContains(t, string(content), "direct debug")
+}
+
+func TestComponentsConcurrent(t *testing.T) {
+	lg := logrus.New()
+	out := &bytes.Buffer{}
+	lg.SetOutput(out)
+	hook := lTest.NewLocal(lg)
+	cs, err := NewComponents(lg, "info")
+	req.NoError(t, err)
+
+	//both loggers write to the same buffer at once
+	const count = 50
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < count; i++ {
+			lg.Info("direct")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < count; i++ {
+			cs.Named("x").Info("component")
+		}
+	}()
+	wg.Wait()
+	req.Len(t, strings.Split(strings.TrimSpace(out.String()), "\n"), 2*count)
+	req.Len(t, hook.AllEntries(), 2*count)
+}
@@ -0,0 +1,226 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 13:05:12
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 13:05:12
+ * @FilePath: /xlogrus/context_hook.go
+ * @Description: add fields from entry.Context by registered extractors
+ *
+ */
+
+package xlogrus
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// ContextExtractor pulls fields out of context, nil if nothing found
+type ContextExtractor func(ctx context.Context) logrus.Fields
+
+/*ContextHook
+ * @msg logrus hook to add fields from entry.Context, such as
+ *		lg.WithContext(ctx).Info() or gorm logger with ctx.
+ *		fields set by WithFields are not overwritten
+ */
+type ContextHook struct {
+	mu         sync.RWMutex
+	extractors []ContextExtractor
+}
+
+// NewContextHook creates hook with extractors, DefaultContextExtractors is used if empty
+func NewContextHook(extractors ...ContextExtractor) *ContextHook {
+	if len(extractors) == 0 {
+		extractors = DefaultContextExtractors()
+	}
+	return &ContextHook{extractors: extractors}
+}
+
+// Register adds extractors, it's safe to call when logging.
+func (h *ContextHook) Register(extractors ...ContextExtractor) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.extractors = append(h.extractors, extractors...)
+}
+
+// Levels implements logrus.Hook
+func (h *ContextHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook
+func (h *ContextHook) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+	ctx := hookContext(entry.Context)
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, ex := range h.extractors {
+		for k, v := range ex(ctx) {
+			if _, ok := entry.Data[k]; !ok {
+				entry.Data[k] = v
+			}
+		}
+	}
+	return nil
+}
+
+// hookContext uses request context for *gin.Context such as lg.WithContext(ctx) in handlers,
+// keys set by ctx.Set are still found
+func hookContext(ctx context.Context) context.Context {
+	gCtx, ok := ctx.(*gin.Context)
+	if !ok || gCtx.Request == nil {
+		return ctx
+	}
+	return ginKeysContext{Context: gCtx.Request.Context(), gCtx: gCtx}
+}
+
+// ginKeysContext looks up string keys of gin ctx first, then request context
+type ginKeysContext struct {
+	context.Context
+	gCtx *gin.Context
+}
+
+func (c ginKeysContext) Value(key interface{}) interface{} {
+	if k, ok := key.(string); ok {
+		if v, exists := c.gCtx.Get(k); exists {
+			return v
+		}
+	}
+	return c.Context.Value(key)
+}
+
+// DefaultContextExtractors returns extractors for request id, trace and span id, user id
+func DefaultContextExtractors() []ContextExtractor {
+	return []ContextExtractor{
+		RequestIDExtractor,
+		TraceExtractor,
+		UserIDExtractor,
+	}
+}
+
+/*ValueExtractor
+ * @msg extractor to set field with the first non-empty value of keys
+ * @param field name of field
+ * @param keys context keys, such as "tenant" for gin ctx.Set("tenant", v)
+ * @return: ContextExtractor
+ */
+func ValueExtractor(field string, keys ...interface{}) ContextExtractor {
+	return func(ctx context.Context) logrus.Fields {
+		if v := lookupValue(ctx, keys...); v != nil {
+			return logrus.Fields{field: v}
+		}
+		return nil
+	}
+}
+
+// common keys, string keys also work with gin ctx.Set()
+var (
+	requestIDKeys = []interface{}{requestIDCtxKey{}, "requestID", "request_id", "X-Request-ID"}
+	userIDKeys    = []interface{}{userIDCtxKey{}, "userID", "user_id"}
+)
+
+// RequestIDExtractor sets field requestID
+func RequestIDExtractor(ctx context.Context) logrus.Fields {
+	return ValueExtractor("requestID", requestIDKeys...)(ctx)
+}
+
+// UserIDExtractor sets field userID
+func UserIDExtractor(ctx context.Context) logrus.Fields {
+	return ValueExtractor("userID", userIDKeys...)(ctx)
+}
+
+// TraceExtractor sets fields traceId and spanId from SpanContext, traceparent or trace_id/span_id keys
+func TraceExtractor(ctx context.Context) logrus.Fields {
+	sc, ok := SpanFromContext(ctx)
+	if !ok {
+		if tp, isStr := ctx.Value("traceparent").(string); isStr {
+			sc, ok = parseTraceparent(tp)
+		}
+	}
+	if !ok {
+		traceID, _ := lookupValue(ctx, "traceId", "traceID", "trace_id").(string)
+		spanID, _ := lookupValue(ctx, "spanId", "spanID", "span_id").(string)
+		sc = SpanContext{TraceID: traceID, SpanID: spanID}
+	}
+	fields := logrus.Fields{}
+	if sc.TraceID != "" {
+		fields["traceId"] = sc.TraceID
+	}
+	if sc.SpanID != "" {
+		fields["spanId"] = sc.SpanID
+	}
+	return fields
+}
+
+// lookupValue returns the first non-empty value of keys
+func lookupValue(ctx context.Context, keys ...interface{}) interface{} {
+	for _, k := range keys {
+		v := ctx.Value(k)
+		if s, ok := v.(string); v == nil || ok && s == "" {
+			continue
+		}
+		return v
+	}
+	return nil
+}
+
+type requestIDCtxKey struct{}
+type userIDCtxKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying request id.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDCtxKey{}, id)
+}
+
+// RequestIDFromContext returns request id carried by ctx, empty if not found.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := lookupValue(ctx, requestIDKeys...).(string)
+	return id
+}
+
+// ContextWithUserID returns a copy of ctx carrying user id.
+func ContextWithUserID(ctx context.Context, id interface{}) context.Context {
+	return context.WithValue(ctx, userIDCtxKey{}, id)
+}
+
+/*parseTraceparent
+ * @msg parse W3C traceparent such as
+ *		00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+ * @param h header value
+ * @return: SpanContext
+ * @return: bool false if invalid
+ */
+func parseTraceparent(h string) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(h), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" ||
+		//version 00 has exactly 4 parts
+		parts[0] == "00" && len(parts) != 4 {
+		return SpanContext{}, false
+	}
+	traceID, spanID, flags := parts[1], parts[2], parts[3]
+	if len(traceID) != 32 || len(spanID) != 16 || len(flags) != 2 ||
+		!isHex(parts[0]) || !isHex(traceID) || !isHex(spanID) || !isHex(flags) ||
+		strings.Trim(traceID, "0") == "" || strings.Trim(spanID, "0") == "" {
+		return SpanContext{}, false
+	}
+	return SpanContext{TraceID: traceID, SpanID: spanID, TraceFlags: flags}, true
+}
+
+// isHex reports whether s is lower-case hex
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !('0' <= s[i] && s[i] <= '9' || 'a' <= s[i] && s[i] <= 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 13:36:50
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 13:36:50
+ * @FilePath: /xlogrus/context_hook_test.go
+ * @Description: test fields extracted from context
+ *
+ */
+
+package xlogrus
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	lTest "github.com/sirupsen/logrus/hooks/test" //logrus tools for test
+	req "github.com/stretchr/testify/require"     //exit if failed
+)
+
+type tenantKey struct{}
+
+func TestContextHook(t *testing.T) {
+	hook := NewContextHook()
+	hook.Register(ValueExtractor("tenant", tenantKey{}))
+	lg, opt, err := NewUserLog(
+		WithLogPath[UserOpt](t.TempDir()+"/"),
+		WithHooks[UserOpt](hook),
+	)
+	req.NoError(t, err)
+	defer opt.Close()
+	lg.SetOutput(io.Discard)
+	last := lTest.NewLocal(lg)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want logrus.Fields
+	}{
+		{"typedKeys",
+			ContextWithUserID(ContextWithRequestID(context.Background(), "req-1"), 42),
+			logrus.Fields{"requestID": "req-1", "userID": 42},
+		},
+		{"stringKeys",
+			context.WithValue(context.WithValue(context.Background(), "request_id", "req-2"),
+				"traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"),
+			logrus.Fields{"requestID": "req-2",
+				"traceId": "4bf92f3577b34da6a3ce929d0e0e4736", "spanId": "00f067aa0ba902b7"},
+		},
+		{"spanContext",
+			ContextWithSpan(context.WithValue(context.Background(), tenantKey{}, "acme"),
+				SpanContext{TraceID: "0af7651916cd43dd8448eb211c80319c", SpanID: "b7ad6b7169203331"}),
+			logrus.Fields{"tenant": "acme",
+				"traceId": "0af7651916cd43dd8448eb211c80319c", "spanId": "b7ad6b7169203331"},
+		},
+		{"invalidTraceparent",
+			context.WithValue(context.Background(), "traceparent", "00-xyz-01"),
+			logrus.Fields{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg.WithContext(tt.ctx).Info("ctx")
+			req.Equal(t, tt.want, last.LastEntry().Data)
+		})
+	}
+
+	//fields set by WithFields win
+	lg.WithContext(ContextWithRequestID(context.Background(), "req-3")).
+		WithField("requestID", "manual").Info("ctx")
+	req.Equal(t, "manual", last.LastEntry().Data["requestID"])
+}
+
+func TestContextHookFile(t *testing.T) {
+	lg, opt, err := NewUserLog(
+		WithLogPath[UserOpt](t.TempDir()+"/"),
+		WithHooks[UserOpt](NewContextHook()),
+	)
+	req.NoError(t, err)
+	defer opt.Close()
+	lg.SetOutput(io.Discard)
+
+	lg.WithContext(ContextWithRequestID(context.Background(), "req-file")).Error("ctx")
+	//fields are added before file hooks write
+	for _, w := range opt.Writers() {
+		data, err := os.ReadFile(w.CurrentFileName())
+		req.NoError(t, err)
+		req.Contains(t, string(data), "requestID=req-file", w.CurrentFileName())
+	}
+}
+
+func TestContextHookGin(t *testing.T) {
+	lg := logrus.New()
+	lg.SetOutput(io.Discard)
+	lg.AddHook(NewContextHook(DefaultContextExtractors()...))
+	lg.AddHook(NewContextHook(ValueExtractor("tenant", "tenant")))
+	last := lTest.NewLocal(lg)
+
+	r, _, _ := newTestGin(t, WithRequestID[GinOpt](RequestIDUUID), WithTracePropagation[GinOpt](true))
+	var record otlpLogRecord
+	r.GET("/trace", func(ctx *gin.Context) {
+		ctx.Set("tenant", "acme")
+		//gin ctx itself, values of request context are not looked up by gin
+		lg.WithContext(ctx).Info("handle")
+		record = toOTLPRecord(last.LastEntry())
+	})
+	request := httptest.NewRequest(http.MethodGet, "/trace", nil)
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), request)
+
+	data := last.LastEntry().Data
+	req.NotEmpty(t, data["requestID"])
+	req.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", data["traceId"])
+	req.NotEmpty(t, data["spanId"])
+	req.Equal(t, "acme", data["tenant"])
+	req.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record.TraceID)
+}
@@ -0,0 +1,94 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 14:02:25
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 14:02:25
+ * @FilePath: /xlogrus/context_log.go
+ * @Description: logger entry carried by context.Context
+ *
+ */
+
+package xlogrus
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// loggerCtxKey is shared by gin middleware, gorm logger and user code
+type loggerCtxKey struct{}
+
+/*WithLogger
+ * @msg returns a copy of ctx carrying entry, its fields show up in gin and gorm logs.
+ *		for *gin.Context, the request context is replaced and gin ctx itself is returned
+ * @param ctx
+ * @param entry
+ * @return: context.Context
+ */
+func WithLogger(ctx context.Context, entry *logrus.Entry) context.Context {
+	if gCtx, ok := ctx.(*gin.Context); ok && gCtx.Request != nil {
+		gCtx.Request = gCtx.Request.WithContext(
+			context.WithValue(gCtx.Request.Context(), loggerCtxKey{}, entry))
+		return gCtx
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, loggerCtxKey{}, entry)
+}
+
+// FromContext returns entry carried by ctx with accumulated fields,
+// or entry of logrus standard logger if not found.
+func FromContext(ctx context.Context) *logrus.Entry {
+	ctx = requestContext(ctx)
+	if ctx == nil {
+		return logrus.NewEntry(logrus.StandardLogger())
+	}
+	entry := entryFromContext(ctx)
+	if entry == nil {
+		return logrus.NewEntry(logrus.StandardLogger()).WithContext(ctx)
+	}
+	if entry.Logger == nil {
+		//fields added by AddFields without logger
+		return logrus.NewEntry(logrus.StandardLogger()).WithFields(entry.Data).WithContext(ctx)
+	}
+	return entry.WithContext(ctx)
+}
+
+// AddFields returns a copy of ctx with fields added to its entry, if there is no entry
+// only fields are carried, so logger is chosen by later WithLogger or gin middleware.
+func AddFields(ctx context.Context, fields logrus.Fields) context.Context {
+	entry := entryFromContext(requestContext(ctx))
+	if entry == nil {
+		entry = &logrus.Entry{Data: logrus.Fields{}}
+	}
+	return WithLogger(ctx, entry.WithFields(fields))
+}
+
+// fieldsFromContext returns fields of entry carried by ctx, nil if not found
+func fieldsFromContext(ctx context.Context) logrus.Fields {
+	if entry := entryFromContext(requestContext(ctx)); entry != nil {
+		return entry.Data
+	}
+	return nil
+}
+
+// entryFromContext returns entry carried by ctx, its Logger is nil if it's set by AddFields only
+func entryFromContext(ctx context.Context) *logrus.Entry {
+	if ctx == nil {
+		return nil
+	}
+	entry, _ := ctx.Value(loggerCtxKey{}).(*logrus.Entry)
+	return entry
+}
+
+// requestContext uses request context for *gin.Context, which is not looked up by gin
+// unless ContextWithFallback is enabled
+func requestContext(ctx context.Context) context.Context {
+	if gCtx, ok := ctx.(*gin.Context); ok && gCtx.Request != nil {
+		return gCtx.Request.Context()
+	}
+	return ctx
+}
@@ -0,0 +1,93 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 14:30:41
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 14:30:41
+ * @FilePath: /xlogrus/context_log_test.go
+ * @Description: test fields shared by context between user, gin and gorm logs
+ *
+ */
+
+package xlogrus
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	lTest "github.com/sirupsen/logrus/hooks/test" //logrus tools for test
+	req "github.com/stretchr/testify/require"     //exit if failed
+)
+
+func TestContextLogger(t *testing.T) {
+	path := t.TempDir() + "/"
+	userLg, userOpt, err := NewUserLog(WithLogPath[UserOpt](path))
+	req.NoError(t, err)
+	defer userOpt.Close()
+	userLg.SetOutput(io.Discard)
+	userHook := lTest.NewLocal(userLg)
+
+	ginLg, ginHandler, ginOpt, err := NewGinLog(WithLogPath[GinOpt](path))
+	req.NoError(t, err)
+	defer ginOpt.Close()
+	ginLg.SetOutput(io.Discard)
+	ginHook := lTest.NewLocal(ginLg)
+
+	gormLg, gormOpt, err := NewGormLog(WithLogPath[GormOpt](path))
+	req.NoError(t, err)
+	defer gormOpt.Close()
+	gormLg.Logger.SetOutput(io.Discard)
+	gormHook := lTest.NewLocal(gormLg.Logger)
+
+	//no entry in context
+	req.Equal(t, logrus.StandardLogger(), FromContext(context.Background()).Logger)
+	//only fields are carried without entry
+	fieldsCtx := AddFields(context.Background(), logrus.Fields{"job": "sync"})
+	req.Nil(t, entryFromContext(fieldsCtx).Logger)
+	req.Equal(t, logrus.StandardLogger(), FromContext(fieldsCtx).Logger)
+	req.Equal(t, "sync", FromContext(fieldsCtx).Data["job"])
+	entry := FromContext(WithLogger(fieldsCtx, userLg.WithFields(fieldsFromContext(fieldsCtx))))
+	req.Equal(t, userLg, entry.Logger)
+	req.Equal(t, "sync", entry.Data["job"])
+
+	r := gin.New()
+	r.Use(func(ctx *gin.Context) {
+		//outer layer sets fields
+		WithLogger(ctx, userLg.WithField("tenant", "acme"))
+		ctx.Next()
+	}, ginHandler)
+	r.GET("/order", func(ctx *gin.Context) {
+		AddFields(ctx, logrus.Fields{"orderID": 7})
+		FromContext(ctx).Info("handle order")
+		gormLg.Warn(ctx.Request.Context(), "slow")
+		ctx.Status(http.StatusOK)
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/order", nil))
+
+	for _, entry := range []*logrus.Entry{userHook.LastEntry(), gormHook.LastEntry(), ginHook.LastEntry()} {
+		req.Equal(t, "acme", entry.Data["tenant"])
+		req.Equal(t, 7, entry.Data["orderID"])
+	}
+	req.Equal(t, "handle order", userHook.LastEntry().Message)
+	req.Equal(t, "/order", ginHook.LastEntry().Data["path"])
+
+	//fields added before middleware are logged by gin logger
+	r = gin.New()
+	r.Use(func(ctx *gin.Context) {
+		AddFields(ctx, logrus.Fields{"tenant": "acme"})
+		ctx.Next()
+	}, ginHandler)
+	r.GET("/order", func(ctx *gin.Context) {
+		req.Equal(t, ginLg, GinLogger(ctx).Logger)
+		GinLogger(ctx).Info("handle order")
+	})
+	ginHook.Reset()
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/order", nil))
+	req.Len(t, ginHook.AllEntries(), 2)
+	req.Equal(t, "handle order", ginHook.Entries[0].Message)
+	req.Equal(t, "acme", ginHook.Entries[0].Data["tenant"])
+}
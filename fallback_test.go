@@ -0,0 +1,123 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 09:40:02
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 09:40:02
+ * @FilePath: /xlogrus/fallback_test.go
+ * @Description: test fallback chain when writing log file failed
+ *
+ */
+
+package xlogrus
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	c "github.com/justin-ren/xlogrus/common"
+	"github.com/pkg/errors"
+	req "github.com/stretchr/testify/require" //exit if failed
+)
+
+// brokenWriter fails until it's fixed
+type brokenWriter struct {
+	broken bool
+	buf    bytes.Buffer
+}
+
+func (w *brokenWriter) Write(p []byte) (int, error) {
+	if w.broken {
+		return 0, errors.New("no space left on device")
+	}
+	return w.buf.Write(p)
+}
+
+func TestFallbackWriter(t *testing.T) {
+	primary := &brokenWriter{broken: true}
+	var alt bytes.Buffer
+	w := c.NewFallbackWriter("trace.log", 10*time.Millisecond, primary, &alt)
+
+	//primary failed, switch to alternate
+	_, err := w.Write([]byte("first\n"))
+	req.NoError(t, err)
+	req.Equal(t, 1, w.Active())
+	req.Equal(t, uint64(1), w.WriteErrors())
+	req.ErrorContains(t, w.LastError(), "no space left")
+	req.Equal(t, "first\n", alt.String())
+
+	//primary is not retried before interval
+	primary.broken = false
+	_, err = w.Write([]byte("second\n"))
+	req.NoError(t, err)
+	req.Equal(t, "first\nsecond\n", alt.String())
+
+	//recover after interval
+	time.Sleep(20 * time.Millisecond)
+	_, err = w.Write([]byte("third\n"))
+	req.NoError(t, err)
+	req.Equal(t, 0, w.Active())
+	req.Equal(t, "third\n", primary.buf.String())
+
+	//all sinks failed
+	primary.broken = true
+	w = c.NewFallbackWriter("trace.log", time.Second, primary)
+	_, err = w.Write([]byte("lost\n"))
+	req.Error(t, err)
+}
+
+func TestUserLogWriteErrors(t *testing.T) {
+	lg, opt, err := NewUserLog(
+		WithLogPath[UserOpt](t.TempDir()+"/"),
+		WithFallbackPath[UserOpt](t.TempDir()+"/"),
+		WithRetryInterval[UserOpt](time.Minute),
+	)
+	req.NoError(t, err)
+	defer opt.Close()
+	lg.Info("fallback")
+	req.Len(t, opt.Writers(), 2)
+	req.Equal(t, uint64(0), opt.WriteErrors())
+	req.NoError(t, opt.LastWriteError())
+}
+
+func TestUserLogFallback(t *testing.T) {
+	logPath, fallbackPath := t.TempDir()+"/logs", t.TempDir()+"/"
+	lg, opt, err := NewUserLog(
+		WithLogPath[UserOpt](logPath+"/"),
+		WithFallbackPath[UserOpt](fallbackPath),
+		WithFallbackStderr[UserOpt](false),
+		WithRetryInterval[UserOpt](time.Nanosecond),
+	)
+	req.NoError(t, err)
+	defer opt.Close()
+	lg.SetOutput(io.Discard)
+	//log files cannot be created even by root if LogPath is a file
+	req.NoError(t, os.RemoveAll(logPath))
+	req.NoError(t, os.WriteFile(logPath, nil, 0644))
+
+	lg.Error("to error.log")
+	lg.Info("to fallback")
+	writers := opt.Writers()
+	req.Len(t, writers, 2)
+	req.Equal(t, 1, writers[0].Active())
+	req.Equal(t, uint64(3), opt.WriteErrors())
+	//the most recent one, not the last writer in order
+	req.ErrorContains(t, opt.LastWriteError(), "failed to write "+writers[0].Name+":")
+
+	files, err := os.ReadDir(fallbackPath)
+	req.NoError(t, err)
+	var content []byte
+	for _, f := range files {
+		if !f.Type().IsRegular() {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(fallbackPath, f.Name()))
+		req.NoError(t, err)
+		content = append(content, b...)
+	}
+	req.Contains(t, string(content), "to fallback")
+	req.Contains(t, string(content), "to error.log")
+}
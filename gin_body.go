@@ -0,0 +1,190 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 20:31:07
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 20:31:07
+ * @FilePath: /xlogrus/gin_body.go
+ * @Description: capture request body for gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	c "github.com/justin-ren/xlogrus/common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// StatusRange is range of status code, both From and To are included
+type StatusRange struct {
+	From int
+	To   int
+}
+
+// StatusClass returns range for status class, such as 4 for 400-499
+func StatusClass(class int) StatusRange {
+	return StatusRange{class * 100, class*100 + 99}
+}
+
+// StatusCode returns range for one status code, such as 404
+func StatusCode(code int) StatusRange {
+	return StatusRange{code, code}
+}
+
+// Contains reports whether status is in range.
+func (r StatusRange) Contains(status int) bool {
+	return r.From <= status && status <= r.To
+}
+
+// inStatusRanges reports whether status is in any range, true if ranges is empty
+func inStatusRanges(ranges []StatusRange, status int) bool {
+	if len(ranges) == 0 {
+		return true
+	}
+	for _, r := range ranges {
+		if r.Contains(status) {
+			return true
+		}
+	}
+	return false
+}
+
+type BodyCapture struct {
+	//max bytes captured, the rest is not logged
+	MaxBytes int
+	//content type prefix, json and urlencoded form if empty
+	ContentTypes []string
+	//log body only for these status, all status if empty
+	Statuses []StatusRange
+	//redact values with banned keywords, password and pwd if empty
+	Keywords []BannedKeyword
+	//capture only for these route templates or path globs, such as /users/:id, /api/**
+	//all routes if empty
+	Routes []string
+}
+
+// defaultBodyTypes is content types captured by default
+var defaultBodyTypes = []string{"application/json", "application/x-www-form-urlencoded"}
+
+// normalize validates capture and fills default values
+func (b *BodyCapture) normalize() error {
+	if b.MaxBytes <= 0 {
+		return errors.New("max bytes of body must be positive")
+	}
+	if len(b.ContentTypes) == 0 {
+		b.ContentTypes = defaultBodyTypes
+	}
+	if len(b.Keywords) == 0 {
+		b.Keywords = defaultBKeywords()
+	}
+	return nil
+}
+
+// matchType reports whether content type is captured
+func (b *BodyCapture) matchType(contentType string) bool {
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
+	for _, t := range b.ContentTypes {
+		if strings.HasPrefix(contentType, strings.ToLower(t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchRoute reports whether route template or path of request matches Routes
+func (b *BodyCapture) matchRoute(ctx *gin.Context) bool {
+	return matchRoutes(b.Routes, ctx)
+}
+
+// SetRequestBody sets capture of request body.
+func (opt *GinOpt) SetRequestBody(capture BodyCapture) error {
+	if err := capture.normalize(); err != nil {
+		return err
+	}
+	opt.RequestBody = &capture
+	return nil
+}
+
+// WithRequestBody 设置记录请求体，如4xx时记录前N字节的json
+func WithRequestBody[
+	T any,
+	PT interface {
+		*T
+		SetRequestBody(BodyCapture) error
+	},
+](capture BodyCapture) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetRequestBody(capture)
+	})
+}
+
+// capturedBody is request body read before handler
+type capturedBody struct {
+	contentType string
+	data        []byte
+	truncated   bool
+}
+
+// replayBody returns read bytes first, then the rest of original body
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
+
+/*captureRequestBody
+ * @msg read up to MaxBytes of request body, the body is restored for handler
+ * @receiver opt
+ * @param ctx
+ * @return: *capturedBody nil if not captured
+ */
+func (opt *GinOpt) captureRequestBody(ctx *gin.Context) *capturedBody {
+	if opt.RequestBody == nil || ctx.Request.Body == nil || ctx.Request.Body == http.NoBody {
+		return nil
+	}
+	contentType := ctx.ContentType()
+	if !opt.RequestBody.matchType(contentType) || !opt.RequestBody.matchRoute(ctx) {
+		return nil
+	}
+	body := ctx.Request.Body
+	//read one more byte to know if it's truncated
+	data, err := io.ReadAll(io.LimitReader(body, int64(opt.RequestBody.MaxBytes)+1))
+	ctx.Request.Body = replayBody{io.MultiReader(bytes.NewReader(data), errReader{err}, body), body}
+	captured := &capturedBody{contentType: contentType, data: data}
+	if len(data) > opt.RequestBody.MaxBytes {
+		captured.data, captured.truncated = data[:opt.RequestBody.MaxBytes], true
+	}
+	return captured
+}
+
+// errReader returns read error of captured body to handler, io.EOF if nil
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return 0, io.EOF
+}
+
+// requestBodyFields returns redacted body if status matches
+func (opt *GinOpt) requestBodyFields(captured *capturedBody, status int) logrus.Fields {
+	if captured == nil || !inStatusRanges(opt.RequestBody.Statuses, status) {
+		return nil
+	}
+	fields := logrus.Fields{
+		"reqBody": redactBody(opt.RequestBody.Keywords, captured.contentType, captured.data),
+	}
+	if captured.truncated {
+		fields["reqBodyTruncated"] = true
+	}
+	return fields
+}
@@ -0,0 +1,79 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 20:58:31
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 20:58:31
+ * @FilePath: /xlogrus/gin_body_test.go
+ * @Description: test body capture in gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	req "github.com/stretchr/testify/require" //exit if failed
+)
+
+func TestGinRequestBody(t *testing.T) {
+	r, hook, _ := newTestGin(t, WithRequestBody[GinOpt](BodyCapture{
+		MaxBytes: 64,
+		Statuses: []StatusRange{StatusClass(4)},
+	}))
+	r.POST("/login", func(ctx *gin.Context) {
+		//handler still reads the whole body
+		body, err := io.ReadAll(ctx.Request.Body)
+		req.NoError(t, err)
+		ctx.String(http.StatusBadRequest, "%d", len(body))
+	})
+	r.POST("/ok", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name        string
+		url         string
+		contentType string
+		body        string
+		want        interface{}
+		truncated   bool
+	}{
+		{"json", "/login", "application/json",
+			`{"user":"tom","Password":"secret","profile":{"pwd":"1"}}`,
+			`{"Password":"***","profile":{"pwd":"***"},"user":"tom"}`, false},
+		{"form", "/login", "application/x-www-form-urlencoded; charset=utf-8",
+			"user=tom&password=secret",
+			"password=***&user=tom", false},
+		{"truncated", "/login", "application/json",
+			`{"user":"tom","note":"` + strings.Repeat("x", 80) + `","password":"secret"}`,
+			`{"user":"tom","note":"` + strings.Repeat("x", 42), true},
+		{"otherType", "/login", "text/plain", "password=secret", nil, false},
+		{"statusNotMatched", "/ok", "application/json", `{"user":"tom"}`, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest("POST", tt.url, strings.NewReader(tt.body))
+			request.Header.Set("Content-Type", tt.contentType)
+			response := httptest.NewRecorder()
+			r.ServeHTTP(response, request)
+			if tt.url == "/login" {
+				req.Equal(t, strconv.Itoa(len(tt.body)), response.Body.String())
+			}
+
+			data := hook.LastEntry().Data
+			req.Equal(t, tt.want, data["reqBody"])
+			if tt.truncated {
+				req.Equal(t, true, data["reqBodyTruncated"])
+			} else {
+				req.NotContains(t, data, "reqBodyTruncated")
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 20:14:06
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 20:14:06
+ * @FilePath: /xlogrus/gin_clf.go
+ * @Description: NCSA common and combined log format for access.log
+ *
+ */
+
+package xlogrus
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	c "github.com/justin-ren/xlogrus/common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// format of access.log
+const (
+	AccessLogText     = "text"     //x-cray text format, same as stdout without color
+	AccessLogCommon   = "common"   //NCSA common log format
+	AccessLogCombined = "combined" //NCSA combined log format with referer and user agent
+)
+
+// time format of %t in common log format
+const clfTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// accessEntryKey marks context of entry logged by gin middleware, value is request start time
+type accessEntryKey struct{}
+
+// withAccessEntry marks ctx as context of access log entry of request received at start
+func withAccessEntry(ctx context.Context, start time.Time) context.Context {
+	return context.WithValue(ctx, accessEntryKey{}, start)
+}
+
+// accessStart returns start time of request if entry is logged by gin middleware
+func accessStart(entry *logrus.Entry) (time.Time, bool) {
+	if entry.Context == nil {
+		return time.Time{}, false
+	}
+	start, ok := entry.Context.Value(accessEntryKey{}).(time.Time)
+	return start, ok
+}
+
+// SetAccessLogFormat sets format of access.log, text, common or combined.
+func (opt *GinOpt) SetAccessLogFormat(format string) error {
+	switch format {
+	case AccessLogText, AccessLogCommon, AccessLogCombined:
+		opt.AccessLogFormat = format
+		return nil
+	}
+	return errors.Errorf("invalid access log format %q", format)
+}
+
+// SetAccessLogLatency sets whether to append latency in microseconds like %D of apache.
+func (opt *GinOpt) SetAccessLogLatency(enabled bool) error {
+	opt.AccessLogLatency = enabled
+	return nil
+}
+
+// WithAccessLogFormat 设置access.log的格式: text, common, combined
+func WithAccessLogFormat[
+	T any,
+	PT interface {
+		*T
+		SetAccessLogFormat(string) error
+	},
+](format string) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetAccessLogFormat(format)
+	})
+}
+
+// WithAccessLogLatency 设置common/combined格式是否在行尾记录微秒耗时(%D)
+func WithAccessLogLatency[
+	T any,
+	PT interface {
+		*T
+		SetAccessLogLatency(bool) error
+	},
+](enabled bool) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetAccessLogLatency(enabled)
+	})
+}
+
+/*CLFFormatter
+ * @msg logrus formatter for NCSA common/combined log format such as
+ *		127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET /a.gif HTTP/1.1" 200 2326 "http://example.com/" "Mozilla/4.08"
+ *		entries which are not logged by gin middleware, such as GinLogger(ctx) in handler,
+ *		are formatted by Fallback, or dropped if Fallback is nil
+ */
+type CLFFormatter struct {
+	//append referer and user agent
+	Combined bool
+	//append latency in microseconds
+	Latency bool
+	//formatter for entries not logged by gin middleware, nil to drop them
+	Fallback logrus.Formatter
+}
+
+// Format implements logrus.Formatter
+func (f *CLFFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	start, ok := accessStart(entry)
+	if !ok {
+		if f.Fallback == nil {
+			return nil, nil
+		}
+		return f.Fallback.Format(entry)
+	}
+	status, _ := entry.Data["statusCode"].(int)
+	b := &bytes.Buffer{}
+	b.WriteString(clfField(entry.Data["clientIP"]))
+	b.WriteString(" - ")
+	b.WriteString(clfField(entry.Data["userID"]))
+	b.WriteString(" [")
+	//%t is time the request was received
+	b.WriteString(start.Format(clfTimeFormat))
+	b.WriteString(`] "`)
+	writeEscaped(b, fmt.Sprintf("%v %v %v", entry.Data["method"], entry.Data["path"], entry.Data["proto"]))
+	b.WriteString(`" `)
+	b.WriteString(strconv.Itoa(status))
+	b.WriteByte(' ')
+	//%b, - for no content
+	if size, _ := entry.Data["dataLength"].(int); size > 0 {
+		b.WriteString(strconv.Itoa(size))
+	} else {
+		b.WriteByte('-')
+	}
+	if f.Combined {
+		for _, k := range []string{"referer", "userAgent"} {
+			b.WriteString(` "`)
+			writeEscaped(b, clfField(entry.Data[k]))
+			b.WriteByte('"')
+		}
+	}
+	if f.Latency {
+		latency, _ := entry.Data["latency"].(time.Duration)
+		b.WriteByte(' ')
+		b.WriteString(strconv.FormatInt(latency.Microseconds(), 10))
+	}
+	b.WriteByte('\n')
+	return b.Bytes(), nil
+}
+
+// clfField returns - for empty value
+func clfField(v interface{}) string {
+	if v == nil {
+		return "-"
+	}
+	s := fmt.Sprint(v)
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// writeEscaped escapes quote, backslash and control chars as apache does
+func writeEscaped(b *bytes.Buffer, s string) {
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; {
+		case ch == '"' || ch == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(ch)
+		case ch < 0x20 || ch == 0x7f:
+			fmt.Fprintf(b, `\x%02x`, ch)
+		default:
+			b.WriteByte(ch)
+		}
+	}
+}
+
+// clfFormatter returns formatter for access.log, nil for text format,
+// logs by handler are kept in stdout and error.log only
+func (opt *GinOpt) clfFormatter() logrus.Formatter {
+	if opt.AccessLogFormat == "" || opt.AccessLogFormat == AccessLogText {
+		return nil
+	}
+	return &CLFFormatter{
+		Combined: opt.AccessLogFormat == AccessLogCombined,
+		Latency:  opt.AccessLogLatency,
+	}
+}
+
+// clfFields returns fields only used by common/combined log format
+func (opt *GinOpt) clfFields(ctx *gin.Context) logrus.Fields {
+	if opt.FileFormatter == nil {
+		return nil
+	}
+	if _, ok := opt.FileFormatter.(*CLFFormatter); !ok {
+		return nil
+	}
+	return logrus.Fields{
+		"proto":     ctx.Request.Proto,
+		"referer":   ctx.Request.Referer(),
+		"userAgent": ctx.Request.UserAgent(),
+	}
+}
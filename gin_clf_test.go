@@ -0,0 +1,109 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 20:40:51
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 20:40:51
+ * @FilePath: /xlogrus/gin_clf_test.go
+ * @Description: test common and combined log format of access.log
+ *
+ */
+
+package xlogrus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	req "github.com/stretchr/testify/require" //exit if failed
+)
+
+func TestCLFFormatter(t *testing.T) {
+	entry := &logrus.Entry{
+		//%t is request start instead of entry time after a slow request
+		Context: withAccessEntry(context.Background(), time.Date(2000, 10, 10, 13, 55, 36, 0, time.FixedZone("", -7*3600))),
+		Time:    time.Date(2000, 10, 10, 13, 55, 42, 0, time.FixedZone("", -7*3600)),
+		Data: logrus.Fields{
+			"statusCode": 200,
+			"clientIP":   "127.0.0.1",
+			"method":     "GET",
+			"path":       "/a.gif",
+			"proto":      "HTTP/1.0",
+			"dataLength": 2326,
+			"referer":    "http://example.com/",
+			"userAgent":  `Mozilla "x"`,
+			"latency":    1500 * time.Microsecond,
+		},
+	}
+	tests := []struct {
+		name string
+		f    *CLFFormatter
+		want string
+	}{
+		{"common", &CLFFormatter{},
+			`127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET /a.gif HTTP/1.0" 200 2326` + "\n"},
+		{"combined", &CLFFormatter{Combined: true, Latency: true},
+			`127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET /a.gif HTTP/1.0" 200 2326 "http://example.com/" "Mozilla \"x\"" 1500` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := tt.f.Format(entry)
+			req.NoError(t, err)
+			req.Equal(t, tt.want, string(b))
+		})
+	}
+
+	//entries by handler are dropped without fallback, even if they have statusCode
+	handlerEntry := &logrus.Entry{Data: logrus.Fields{"statusCode": 200}, Message: "hello"}
+	b, err := (&CLFFormatter{}).Format(handlerEntry)
+	req.NoError(t, err)
+	req.Empty(t, b)
+	b, err = (&CLFFormatter{Fallback: &logrus.JSONFormatter{}}).Format(handlerEntry)
+	req.NoError(t, err)
+	req.Contains(t, string(b), `"msg":"hello"`)
+}
+
+func TestGinAccessLogFormat(t *testing.T) {
+	_, _, _, err := NewGinLog(WithAccessLogFormat[GinOpt]("json"))
+	req.Error(t, err)
+
+	r, hook, opt := newTestGin(t,
+		WithAccessLogFormat[GinOpt](AccessLogCombined),
+		WithAccessLogLatency[GinOpt](true),
+	)
+	r.GET("/users/:id", func(ctx *gin.Context) {
+		GinLogger(ctx).Info("found user")
+		GinLogger(ctx).WithField("statusCode", 404).Info("upstream")
+		ctx.String(http.StatusOK, "ok")
+	})
+	r.GET("/fail", func(ctx *gin.Context) {
+		ctx.Status(http.StatusInternalServerError)
+	})
+	request := httptest.NewRequest(http.MethodGet, "/users/12?a=1", nil)
+	request.Header.Set("User-Agent", "curl/8.0")
+	request.Header.Set("Referer", "http://example.com/")
+	r.ServeHTTP(httptest.NewRecorder(), request)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+	req.Equal(t, "found user", hook.Entries[0].Message)
+	req.Equal(t, "curl/8.0", hook.Entries[2].Data["userAgent"])
+
+	writers := opt.Writers()
+	req.Len(t, writers, 2)
+	access, err := os.ReadFile(writers[0].CurrentFileName())
+	req.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(access)), "\n")
+	req.Len(t, lines, 2)
+	req.Regexp(t, `^192\.0\.2\.1 - - \[.+\] "GET /users/12\?a=1 HTTP/1\.1" 200 2 "http://example\.com/" "curl/8\.0" \d+$`, lines[0])
+	req.Regexp(t, `^192\.0\.2\.1 - - \[.+\] "GET /fail HTTP/1\.1" 500 - "-" "-" \d+$`, lines[1])
+
+	//error.log is still structured
+	errLog, err := os.ReadFile(writers[1].CurrentFileName())
+	req.NoError(t, err)
+	req.Contains(t, string(errLog), "statusCode=500")
+}
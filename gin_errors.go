@@ -0,0 +1,177 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 23:10:48
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 23:10:48
+ * @FilePath: /xlogrus/gin_errors.go
+ * @Description: structured ctx.Errors for gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	c "github.com/justin-ren/xlogrus/common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// max frames of pkg/errors stack logged for each error
+const maxErrorStack = 32
+
+// defaultErrorLevels: bind and public errors are caused by client
+var defaultErrorLevels = map[gin.ErrorType]logrus.Level{
+	gin.ErrorTypeBind:    logrus.WarnLevel,
+	gin.ErrorTypePublic:  logrus.WarnLevel,
+	gin.ErrorTypePrivate: logrus.ErrorLevel,
+	gin.ErrorTypeRender:  logrus.ErrorLevel,
+}
+
+// SetErrorLevels sets log level by gin error type, types not in levels keep default level.
+func (opt *GinOpt) SetErrorLevels(levels map[gin.ErrorType]logrus.Level) error {
+	merged := make(map[gin.ErrorType]logrus.Level, len(defaultErrorLevels))
+	for t, lvl := range defaultErrorLevels {
+		merged[t] = lvl
+	}
+	for t, lvl := range levels {
+		//fatal and panic would exit or break the request
+		if lvl < logrus.ErrorLevel || lvl > logrus.TraceLevel {
+			return errors.Errorf("invalid level %q for error type %s", lvl, errorTypeName(t))
+		}
+		merged[t] = lvl
+	}
+	opt.ErrorLevels = merged
+	return nil
+}
+
+// WithErrorLevels 设置gin错误类型对应的日志级别, 如bind错误为warn
+func WithErrorLevels[
+	T any,
+	PT interface {
+		*T
+		SetErrorLevels(map[gin.ErrorType]logrus.Level) error
+	},
+](levels map[gin.ErrorType]logrus.Level) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetErrorLevels(levels)
+	})
+}
+
+/*errorsEntry
+ * @msg fields, level and message for ctx.Errors
+ * @receiver opt
+ * @param errs ctx.Errors
+ * @return: logrus.Fields errors as array of type, message, meta, cause and stack
+ * @return: logrus.Level the most severe level of errors
+ * @return: string messages joined by "; "
+ */
+func (opt *GinOpt) errorsEntry(errs []*gin.Error) (logrus.Fields, logrus.Level, string) {
+	items := make([]logrus.Fields, 0, len(errs))
+	msgs := make([]string, 0, len(errs))
+	level := logrus.TraceLevel
+	for _, e := range errs {
+		item := logrus.Fields{
+			"type":    errorTypeName(e.Type),
+			"message": e.Error(),
+		}
+		if e.Meta != nil {
+			item["meta"] = e.Meta
+		}
+		if cause := errors.Cause(e.Err); cause != nil && cause.Error() != e.Error() {
+			item["cause"] = cause.Error()
+		}
+		if stack := errorStack(e.Err); len(stack) > 0 {
+			item["stack"] = stack
+		}
+		items = append(items, item)
+		msgs = append(msgs, e.Error())
+		if lvl := opt.errorLevel(e.Type); lvl < level {
+			level = lvl
+		}
+	}
+	return logrus.Fields{"errors": items}, level, strings.Join(msgs, "; ")
+}
+
+// errorLevel returns the most severe level of flags in error type, error if not configured
+func (opt *GinOpt) errorLevel(t gin.ErrorType) logrus.Level {
+	levels := opt.ErrorLevels
+	if levels == nil {
+		levels = defaultErrorLevels
+	}
+	level, found := logrus.TraceLevel, false
+	for flag, lvl := range levels {
+		if t&flag != 0 && lvl <= level {
+			level, found = lvl, true
+		}
+	}
+	if !found {
+		return logrus.ErrorLevel
+	}
+	return level
+}
+
+// errorTypeName returns names of gin error type flags such as "bind", "private|public"
+func errorTypeName(t gin.ErrorType) string {
+	if t == gin.ErrorTypeAny {
+		return "any"
+	}
+	var names []string
+	for _, f := range []struct {
+		flag gin.ErrorType
+		name string
+	}{
+		{gin.ErrorTypeBind, "bind"},
+		{gin.ErrorTypeRender, "render"},
+		{gin.ErrorTypePrivate, "private"},
+		{gin.ErrorTypePublic, "public"},
+	} {
+		if t&f.flag != 0 {
+			names = append(names, f.name)
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Sprintf("0x%x", uint64(t))
+	}
+	return strings.Join(names, "|")
+}
+
+/*errorStack
+ * @msg stack of the innermost pkg/errors error in chain, such as
+ *		"main.handler /app/main.go:12"
+ * @param err
+ * @return: []string nil if no stack found
+ */
+func errorStack(err error) []string {
+	type stackTracer interface {
+		StackTrace() errors.StackTrace
+	}
+	var trace errors.StackTrace
+	for err != nil {
+		if st, ok := err.(stackTracer); ok {
+			trace = st.StackTrace()
+		}
+		err = errors.Unwrap(err)
+	}
+	if len(trace) == 0 {
+		return nil
+	}
+	if len(trace) > maxErrorStack {
+		trace = trace[:maxErrorStack]
+	}
+	stack := make([]string, 0, len(trace))
+	for _, f := range trace {
+		//pc of errors.Frame is return address
+		fn := runtime.FuncForPC(uintptr(f) - 1)
+		if fn == nil {
+			continue
+		}
+		file, line := fn.FileLine(uintptr(f) - 1)
+		stack = append(stack, fmt.Sprintf("%s %s:%d", fn.Name(), file, line))
+	}
+	return stack
+}
@@ -0,0 +1,82 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 23:27:35
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 23:27:35
+ * @FilePath: /xlogrus/gin_errors_test.go
+ * @Description: test structured ctx.Errors of gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	req "github.com/stretchr/testify/require" //exit if failed
+)
+
+func TestGinErrors(t *testing.T) {
+	_, _, _, err := NewGinLog(WithErrorLevels[GinOpt](map[gin.ErrorType]logrus.Level{gin.ErrorTypeBind: logrus.FatalLevel}))
+	req.Error(t, err)
+
+	r, hook, _ := newTestGin(t, WithErrorLevels[GinOpt](map[gin.ErrorType]logrus.Level{gin.ErrorTypePublic: logrus.InfoLevel}))
+	r.GET("/bind", func(ctx *gin.Context) {
+		_ = ctx.Error(errors.New("invalid name")).SetType(gin.ErrorTypeBind).SetMeta(gin.H{"field": "name"})
+		ctx.Status(http.StatusBadRequest)
+	})
+	r.GET("/public", func(ctx *gin.Context) {
+		_ = ctx.Error(errors.New("not allowed")).SetType(gin.ErrorTypePublic)
+		ctx.Status(http.StatusOK)
+	})
+	r.GET("/private", func(ctx *gin.Context) {
+		_ = ctx.Error(errors.New("not allowed")).SetType(gin.ErrorTypePublic)
+		_ = ctx.Error(errors.Wrap(errors.New("connection refused"), "query user"))
+		ctx.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		path      string
+		wantLevel logrus.Level
+		wantMsg   string
+		wantTypes []string
+	}{
+		{"/bind", logrus.WarnLevel, "invalid name", []string{"bind"}},
+		//status level is used if it's more severe
+		{"/public", logrus.InfoLevel, "not allowed", []string{"public"}},
+		{"/private", logrus.ErrorLevel, "not allowed; query user: connection refused", []string{"public", "private"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+			entry := hook.LastEntry()
+			req.Equal(t, tt.wantLevel, entry.Level)
+			req.Equal(t, tt.wantMsg, entry.Message)
+			items := entry.Data["errors"].([]logrus.Fields)
+			req.Len(t, items, len(tt.wantTypes))
+			for i, typ := range tt.wantTypes {
+				req.Equal(t, typ, items[i]["type"])
+				stack := items[i]["stack"].([]string)
+				req.True(t, strings.HasPrefix(stack[0], "github.com/justin-ren/xlogrus.TestGinErrors."), stack[0])
+			}
+		})
+	}
+
+	entry := hook.Entries[0]
+	req.Equal(t, gin.H{"field": "name"}, entry.Data["errors"].([]logrus.Fields)[0]["meta"])
+	wrapped := hook.LastEntry().Data["errors"].([]logrus.Fields)[1]
+	req.Equal(t, "connection refused", wrapped["cause"])
+}
+
+func TestErrorTypeName(t *testing.T) {
+	req.Equal(t, "any", errorTypeName(gin.ErrorTypeAny))
+	req.Equal(t, "bind|private", errorTypeName(gin.ErrorTypeBind|gin.ErrorTypePrivate))
+	req.Equal(t, "0x4", errorTypeName(4))
+	req.Nil(t, errorStack(nil))
+}
@@ -0,0 +1,172 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 22:15:33
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 22:15:33
+ * @FilePath: /xlogrus/gin_header.go
+ * @Description: log request and response headers in gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"net/http"
+	"strings"
+
+	c "github.com/justin-ren/xlogrus/common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// headers masked by default
+var defaultMaskHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// canonicalHeaders converts names to canonical format such as User-Agent
+func canonicalHeaders(names []string) []string {
+	headers := make([]string, 0, len(names))
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			headers = append(headers, http.CanonicalHeaderKey(name))
+		}
+	}
+	return headers
+}
+
+// SetRequestHeaders sets request headers logged as reqHeader.<name>.
+func (opt *GinOpt) SetRequestHeaders(names []string) error {
+	opt.RequestHeaders = canonicalHeaders(names)
+	return nil
+}
+
+// SetResponseHeaders sets response headers logged as respHeader.<name>.
+func (opt *GinOpt) SetResponseHeaders(names []string) error {
+	opt.ResponseHeaders = canonicalHeaders(names)
+	return nil
+}
+
+// SetMaskHeaders sets headers whose value is masked, they are added to the default ones
+// such as Authorization, use SetUnmaskHeaders to log the default ones in plain text.
+func (opt *GinOpt) SetMaskHeaders(names []string) error {
+	opt.MaskHeaders = make(map[string]struct{}, len(names)+len(defaultMaskHeaders))
+	for _, name := range canonicalHeaders(append(names, defaultMaskHeaders...)) {
+		opt.MaskHeaders[name] = struct{}{}
+	}
+	return nil
+}
+
+// SetUnmaskHeaders sets headers logged in plain text even if they are masked by default.
+func (opt *GinOpt) SetUnmaskHeaders(names []string) error {
+	opt.UnmaskHeaders = make(map[string]struct{}, len(names))
+	for _, name := range canonicalHeaders(names) {
+		opt.UnmaskHeaders[name] = struct{}{}
+	}
+	return nil
+}
+
+// SetHeaderMaxLen sets max length of every header value.
+func (opt *GinOpt) SetHeaderMaxLen(length int) error {
+	if length <= 0 {
+		return errors.New("header max length must be positive")
+	}
+	opt.HeaderMaxLen = length
+	return nil
+}
+
+// WithRequestHeaders 设置记录的请求头，如User-Agent, Referer
+func WithRequestHeaders[
+	T any,
+	PT interface {
+		*T
+		SetRequestHeaders([]string) error
+	},
+](names []string) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetRequestHeaders(names)
+	})
+}
+
+// WithResponseHeaders 设置记录的响应头，如Content-Type
+func WithResponseHeaders[
+	T any,
+	PT interface {
+		*T
+		SetResponseHeaders([]string) error
+	},
+](names []string) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetResponseHeaders(names)
+	})
+}
+
+// WithMaskHeaders 设置需要隐藏值的请求头，总是包含默认的Authorization, Cookie, Set-Cookie
+func WithMaskHeaders[
+	T any,
+	PT interface {
+		*T
+		SetMaskHeaders([]string) error
+	},
+](names []string) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetMaskHeaders(names)
+	})
+}
+
+// WithUnmaskHeaders 设置不隐藏值的默认请求头，如调试时记录Cookie
+func WithUnmaskHeaders[
+	T any,
+	PT interface {
+		*T
+		SetUnmaskHeaders([]string) error
+	},
+](names []string) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetUnmaskHeaders(names)
+	})
+}
+
+// WithHeaderMaxLen 设置每个请求头记录的最大长度
+func WithHeaderMaxLen[
+	T any,
+	PT interface {
+		*T
+		SetHeaderMaxLen(int) error
+	},
+](length int) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetHeaderMaxLen(length)
+	})
+}
+
+// headerFields returns header values with prefix such as reqHeader.User-Agent
+func (opt *GinOpt) headerFields(prefix string, names []string, header http.Header) logrus.Fields {
+	if len(names) == 0 {
+		return nil
+	}
+	fields := logrus.Fields{}
+	for _, name := range names {
+		values, ok := header[name]
+		if !ok {
+			continue
+		}
+		value := strings.Join(values, ", ")
+		if opt.masked(name) {
+			value = redactedValue
+		} else if anonymized, ok := opt.anonymizeHeader(name, value); ok {
+			value = anonymized
+		} else if len(value) > opt.HeaderMaxLen {
+			value = value[:opt.HeaderMaxLen] + "..."
+		}
+		fields[prefix+name] = value
+	}
+	return fields
+}
+
+// masked reports whether value of header is masked
+func (opt *GinOpt) masked(name string) bool {
+	if _, ok := opt.UnmaskHeaders[name]; ok {
+		return false
+	}
+	_, ok := opt.MaskHeaders[name]
+	return ok
+}
@@ -0,0 +1,84 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 22:40:18
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 22:40:18
+ * @FilePath: /xlogrus/gin_header_test.go
+ * @Description: test header fields in gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	req "github.com/stretchr/testify/require" //exit if failed
+)
+
+func TestGinHeaders(t *testing.T) {
+	r, hook, _ := newTestGin(t,
+		WithRequestHeaders[GinOpt]([]string{"user-agent", "Authorization", "X-Forwarded-For", "Referer"}),
+		WithResponseHeaders[GinOpt]([]string{"Content-Type", "Set-Cookie"}),
+		WithHeaderMaxLen[GinOpt](16),
+	)
+	r.GET("/login", func(ctx *gin.Context) {
+		ctx.SetCookie("session", "secret", 3600, "/", "", false, true)
+		ctx.JSON(http.StatusOK, gin.H{})
+	})
+	request := httptest.NewRequest("GET", "/login", nil)
+	request.Header.Set("User-Agent", "curl/8.0")
+	request.Header.Set("Authorization", "Bearer token")
+	request.Header.Add("X-Forwarded-For", "10.0.0.1")
+	request.Header.Add("X-Forwarded-For", "10.0.0.2, 10.0.0.3")
+	r.ServeHTTP(httptest.NewRecorder(), request)
+
+	data := hook.LastEntry().Data
+	req.Equal(t, "curl/8.0", data["reqHeader.User-Agent"])
+	req.Equal(t, "***", data["reqHeader.Authorization"])
+	req.Equal(t, "10.0.0.1, 10.0.0"+"...", data["reqHeader.X-Forwarded-For"])
+	req.NotContains(t, data, "reqHeader.Referer")
+	req.Equal(t, "application/json"+"...", data["respHeader.Content-Type"])
+	req.Equal(t, "***", data["respHeader.Set-Cookie"])
+
+	//masked headers are added to the default ones
+	r, hook, _ = newTestGin(t,
+		WithRequestHeaders[GinOpt]([]string{"Authorization", "Cookie", "X-Api-Key"}),
+		WithMaskHeaders[GinOpt]([]string{"x-api-key"}),
+	)
+	r.GET("/key", func(ctx *gin.Context) {})
+	request = httptest.NewRequest("GET", "/key", nil)
+	request.Header.Set("Authorization", "Basic "+strings.Repeat("a", 4))
+	request.Header.Set("Cookie", "session=secret")
+	request.Header.Set("X-Api-Key", "secret")
+	r.ServeHTTP(httptest.NewRecorder(), request)
+	req.Equal(t, logrus.Fields{"reqHeader.Authorization": "***", "reqHeader.Cookie": "***", "reqHeader.X-Api-Key": "***"},
+		subFields(hook.LastEntry().Data, "reqHeader."))
+
+	//default ones are logged only by explicit opt-out
+	r, hook, _ = newTestGin(t,
+		WithRequestHeaders[GinOpt]([]string{"Authorization", "Cookie"}),
+		WithUnmaskHeaders[GinOpt]([]string{"cookie"}),
+		WithMaskHeaders[GinOpt](nil),
+	)
+	r.GET("/key", func(ctx *gin.Context) {})
+	r.ServeHTTP(httptest.NewRecorder(), request)
+	req.Equal(t, logrus.Fields{"reqHeader.Authorization": "***", "reqHeader.Cookie": "session=secret"},
+		subFields(hook.LastEntry().Data, "reqHeader."))
+}
+
+// subFields returns fields with prefix
+func subFields(data logrus.Fields, prefix string) logrus.Fields {
+	fields := logrus.Fields{}
+	for k, v := range data {
+		if strings.HasPrefix(k, prefix) {
+			fields[k] = v
+		}
+	}
+	return fields
+}
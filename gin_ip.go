@@ -0,0 +1,300 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 10:05:12
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 10:05:12
+ * @FilePath: /xlogrus/gin_ip.go
+ * @Description: client ip with trusted proxies and anonymization for gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	c "github.com/justin-ren/xlogrus/common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// mode of client ip in access log
+const (
+	IPFull     = "full"     //192.168.1.23
+	IPTruncate = "truncate" //192.168.1.0 for IPv4 /24, 2001:db8:1:: for IPv6 /48
+	IPHash     = "hash"     //HMAC-SHA256 of ip with IPHashKey in hex
+)
+
+// SetClientIPMode sets how client ip is logged, full, truncate or hash.
+func (opt *GinOpt) SetClientIPMode(mode string) error {
+	switch mode {
+	case IPFull, IPTruncate, IPHash:
+		opt.ClientIPMode = mode
+		return nil
+	}
+	return errors.Errorf("invalid client ip mode %q", mode)
+}
+
+// SetIPHashKey sets key of HMAC for hash mode of client ip.
+func (opt *GinOpt) SetIPHashKey(key []byte) error {
+	if len(key) == 0 {
+		return errors.New("ip hash key cannot be empty")
+	}
+	opt.IPHashKey = key
+	return nil
+}
+
+/*SetTrustedProxies
+ * @msg sets proxies whose X-Forwarded-For is trusted, client ip is the
+ *		rightmost untrusted address of the chain. ctx.ClientIP() of gin is used if empty
+ * @receiver opt
+ * @param proxies ip or cidr such as 10.0.0.0/8, 127.0.0.1
+ * @return: error
+ */
+func (opt *GinOpt) SetTrustedProxies(proxies []string) error {
+	nets := make([]*net.IPNet, 0, len(proxies))
+	for _, p := range proxies {
+		if !strings.Contains(p, "/") {
+			ip := net.ParseIP(p)
+			if ip == nil {
+				return errors.Errorf("invalid trusted proxy %q", p)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(p)
+		if err != nil {
+			return errors.Wrapf(err, "invalid trusted proxy %q", p)
+		}
+		nets = append(nets, ipNet)
+	}
+	opt.TrustedProxies = nets
+	return nil
+}
+
+// SetLogForwardedFor sets whether to log X-Forwarded-For chain as forwardedFor.
+func (opt *GinOpt) SetLogForwardedFor(enabled bool) error {
+	opt.LogForwardedFor = enabled
+	return nil
+}
+
+// WithClientIPMode 设置客户端ip的记录方式: full, truncate(IPv4 /24, IPv6 /48), hash
+func WithClientIPMode[
+	T any,
+	PT interface {
+		*T
+		SetClientIPMode(string) error
+	},
+](mode string) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetClientIPMode(mode)
+	})
+}
+
+// WithIPHashKey 设置hash方式下HMAC的密钥
+func WithIPHashKey[
+	T any,
+	PT interface {
+		*T
+		SetIPHashKey([]byte) error
+	},
+](key []byte) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetIPHashKey(key)
+	})
+}
+
+// WithTrustedProxies 设置可信代理的ip或cidr, 用于从X-Forwarded-For解析客户端ip
+func WithTrustedProxies[
+	T any,
+	PT interface {
+		*T
+		SetTrustedProxies([]string) error
+	},
+](proxies []string) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetTrustedProxies(proxies)
+	})
+}
+
+// WithLogForwardedFor 设置是否记录完整的X-Forwarded-For链
+func WithLogForwardedFor[
+	T any,
+	PT interface {
+		*T
+		SetLogForwardedFor(bool) error
+	},
+](enabled bool) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetLogForwardedFor(enabled)
+	})
+}
+
+// checkClientIP validates options which depend on each other
+func (opt *GinOpt) checkClientIP() error {
+	if opt.ClientIPMode == IPHash && len(opt.IPHashKey) == 0 {
+		return errors.New("ip hash key is required for hash mode")
+	}
+	return nil
+}
+
+// key of client ip in gin ctx, it's set by access log middleware for recovery
+const clientIPKey = "xlogrus.clientIP"
+
+// headers carrying client ip, they are anonymized unless ClientIPMode is full
+var ipHeaders = map[string]struct{}{
+	"X-Forwarded-For":  {},
+	"X-Real-Ip":        {},
+	"X-Client-Ip":      {},
+	"True-Client-Ip":   {},
+	"Cf-Connecting-Ip": {},
+}
+
+// clientIP returns client ip for access log, it's anonymized by ClientIPMode
+func (opt *GinOpt) clientIP(ctx *gin.Context) string {
+	ip := ctx.ClientIP()
+	if len(opt.TrustedProxies) > 0 {
+		ip = opt.realIP(ctx.Request)
+	}
+	ip = opt.anonymizeIP(ip)
+	ctx.Set(clientIPKey, ip)
+	return ip
+}
+
+// loggedClientIP returns client ip set by access log middleware, or ctx.ClientIP()
+// if the middleware is not used before
+func loggedClientIP(ctx *gin.Context) string {
+	if ip, ok := ctx.Get(clientIPKey); ok {
+		if s, isStr := ip.(string); isStr {
+			return s
+		}
+	}
+	return ctx.ClientIP()
+}
+
+// anonymizeHeader anonymizes addresses in headers such as X-Forwarded-For, ok is false
+// for other headers
+func (opt *GinOpt) anonymizeHeader(name, value string) (string, bool) {
+	if opt.ClientIPMode == "" || opt.ClientIPMode == IPFull {
+		return value, false
+	}
+	if name == "Forwarded" {
+		//for=, by= and host= are mixed, mask all of it
+		return redactedValue, true
+	}
+	if _, ok := ipHeaders[name]; !ok {
+		return value, false
+	}
+	parts := strings.Split(value, ",")
+	for i := range parts {
+		parts[i] = opt.anonymizeIP(strings.TrimSpace(parts[i]))
+	}
+	return strings.Join(parts, ", "), true
+}
+
+/*realIP
+ * @msg client ip from X-Forwarded-For, addresses of trusted proxies are skipped
+ *		from the right, the leftmost one is used if all of them are trusted.
+ *		remote address is used if an invalid hop is found before any untrusted one,
+ *		so a trusted proxy is never taken as client from a forged chain
+ * @receiver opt
+ * @param r
+ * @return: string
+ */
+func (opt *GinOpt) realIP(r *http.Request) string {
+	remote, _, err := net.SplitHostPort(strings.TrimSpace(r.RemoteAddr))
+	if err != nil {
+		remote = strings.TrimSpace(r.RemoteAddr)
+	}
+	if !opt.trusted(remote) {
+		return remote
+	}
+	chain := forwardedFor(r.Header)
+	ip := remote
+	for i := len(chain) - 1; i >= 0; i-- {
+		if net.ParseIP(chain[i]) == nil {
+			//forged or broken header, addresses so far are all trusted proxies
+			return remote
+		}
+		ip = chain[i]
+		if !opt.trusted(ip) {
+			break
+		}
+	}
+	return ip
+}
+
+// trusted reports whether ip is a trusted proxy
+func (opt *GinOpt) trusted(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, n := range opt.TrustedProxies {
+		if n.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedFor returns addresses of all X-Forwarded-For headers
+func forwardedFor(header http.Header) []string {
+	var chain []string
+	for _, v := range header.Values("X-Forwarded-For") {
+		for _, ip := range strings.Split(v, ",") {
+			if ip = strings.TrimSpace(ip); ip != "" {
+				chain = append(chain, ip)
+			}
+		}
+	}
+	return chain
+}
+
+// anonymizeIP returns ip by ClientIPMode, invalid ip is kept for full mode only
+func (opt *GinOpt) anonymizeIP(ip string) string {
+	if opt.ClientIPMode == "" || opt.ClientIPMode == IPFull {
+		return ip
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		if ip == "" {
+			return ""
+		}
+		return redactedValue
+	}
+	if opt.ClientIPMode == IPHash {
+		mac := hmac.New(sha256.New, opt.IPHashKey)
+		mac.Write([]byte(parsed.String()))
+		return hex.EncodeToString(mac.Sum(nil)[:16])
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return v4.Mask(net.CIDRMask(24, 32)).String()
+	}
+	return parsed.Mask(net.CIDRMask(48, 128)).String()
+}
+
+// forwardedFields returns X-Forwarded-For chain with anonymized addresses
+func (opt *GinOpt) forwardedFields(ctx *gin.Context) logrus.Fields {
+	if !opt.LogForwardedFor {
+		return nil
+	}
+	chain := forwardedFor(ctx.Request.Header)
+	if len(chain) == 0 {
+		return nil
+	}
+	for i := range chain {
+		chain[i] = opt.anonymizeIP(chain[i])
+	}
+	return logrus.Fields{"forwardedFor": strings.Join(chain, ", ")}
+}
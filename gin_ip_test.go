@@ -0,0 +1,163 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 10:31:26
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 10:31:26
+ * @FilePath: /xlogrus/gin_ip_test.go
+ * @Description: test trusted proxies and client ip anonymization
+ *
+ */
+
+package xlogrus
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	lTest "github.com/sirupsen/logrus/hooks/test" //logrus tools for test
+	req "github.com/stretchr/testify/require"     //exit if failed
+)
+
+func TestAnonymizeIP(t *testing.T) {
+	opt := GetGinOpt()
+	tests := []struct {
+		mode string
+		ip   string
+		want string
+	}{
+		{IPFull, "192.168.1.23", "192.168.1.23"},
+		{IPTruncate, "192.168.1.23", "192.168.1.0"},
+		{IPTruncate, "2001:db8:1:2:3::4", "2001:db8:1::"},
+		{IPTruncate, "::ffff:10.1.2.3", "10.1.2.0"},
+		{IPTruncate, "unknown", "***"},
+		{IPTruncate, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode+tt.ip, func(t *testing.T) {
+			req.NoError(t, opt.SetClientIPMode(tt.mode))
+			req.Equal(t, tt.want, opt.anonymizeIP(tt.ip))
+		})
+	}
+
+	req.Error(t, opt.SetClientIPMode("mask"))
+	req.NoError(t, opt.SetClientIPMode(IPHash))
+	req.NoError(t, opt.SetIPHashKey([]byte("k1")))
+	hashed := opt.anonymizeIP("192.168.1.23")
+	req.Len(t, hashed, 32)
+	req.Equal(t, hashed, opt.anonymizeIP("192.168.1.23"))
+	req.NotEqual(t, hashed, opt.anonymizeIP("192.168.1.24"))
+	req.NoError(t, opt.SetIPHashKey([]byte("k2")))
+	req.NotEqual(t, hashed, opt.anonymizeIP("192.168.1.23"))
+}
+
+func TestGinClientIP(t *testing.T) {
+	_, _, _, err := NewGinLog(WithClientIPMode[GinOpt](IPHash))
+	req.Error(t, err, "hash key is required")
+	_, _, _, err = NewGinLog(WithTrustedProxies[GinOpt]([]string{"10.0.0.0/33"}))
+	req.Error(t, err)
+
+	r, hook, _ := newTestGin(t,
+		WithClientIPMode[GinOpt](IPTruncate),
+		WithTrustedProxies[GinOpt]([]string{"10.0.0.0/8", "127.0.0.1"}),
+		WithLogForwardedFor[GinOpt](true),
+	)
+	r.GET("/ip", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+	tests := []struct {
+		name       string
+		remote     string
+		xff        string
+		wantIP     string
+		wantForFor interface{}
+	}{
+		{"direct", "203.0.113.7:5000", "", "203.0.113.0", nil},
+		//header from untrusted client is ignored
+		{"untrustedRemote", "203.0.113.7:5000", "1.2.3.4", "203.0.113.0", "1.2.3.0"},
+		{"trustedChain", "10.0.0.1:5000", "198.51.100.9, 10.1.1.1", "198.51.100.0", "198.51.100.0, 10.1.1.0"},
+		{"spoofedLeft", "127.0.0.1:5000", "6.6.6.6, 198.51.100.9", "198.51.100.0", "6.6.6.0, 198.51.100.0"},
+		{"allTrusted", "10.0.0.1:5000", "10.0.0.2, 10.0.0.3", "10.0.0.0", "10.0.0.0, 10.0.0.0"},
+		{"invalid", "10.0.0.1:5000", "bad, 10.0.0.3", "10.0.0.0", "***, 10.0.0.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			request.RemoteAddr = tt.remote
+			if tt.xff != "" {
+				request.Header.Set("X-Forwarded-For", tt.xff)
+			}
+			r.ServeHTTP(httptest.NewRecorder(), request)
+			entry := hook.LastEntry()
+			req.Equal(t, tt.wantIP, entry.Data["clientIP"])
+			req.Equal(t, tt.wantForFor, entry.Data["forwardedFor"])
+		})
+	}
+}
+
+func TestGinClientIPAnonymized(t *testing.T) {
+	lg, handler, opt, err := NewGinLog(
+		WithLogPath[GinOpt](t.TempDir()+"/"),
+		WithClientIPMode[GinOpt](IPTruncate),
+		WithRequestHeaders[GinOpt]([]string{"X-Forwarded-For", "X-Real-Ip", "Forwarded"}),
+	)
+	req.NoError(t, err)
+	defer opt.Close()
+	lg.SetOutput(io.Discard)
+	hook := lTest.NewLocal(lg)
+	recovery, err := NewGinRecovery(lg)
+	req.NoError(t, err)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(handler, recovery)
+	r.GET("/panic", func(ctx *gin.Context) { panic("boom") })
+
+	request := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	request.RemoteAddr = "203.0.113.7:5000"
+	request.Header.Set("X-Forwarded-For", "198.51.100.9, 10.1.1.1")
+	request.Header.Set("X-Real-Ip", "198.51.100.9")
+	request.Header.Set("Forwarded", "for=198.51.100.9")
+	r.ServeHTTP(httptest.NewRecorder(), request)
+
+	entries := hook.AllEntries()
+	req.Len(t, entries, 2)
+	panicEntry, access := entries[0], entries[1]
+	req.Equal(t, access.Data["clientIP"], panicEntry.Data["clientIP"])
+	req.Equal(t, "198.51.100.0", panicEntry.Data["clientIP"])
+	req.Equal(t, logrus.Fields{
+		"reqHeader.X-Forwarded-For": "198.51.100.0, 10.1.1.0",
+		"reqHeader.X-Real-Ip":       "198.51.100.0",
+		"reqHeader.Forwarded":       "***",
+	}, subFields(access.Data, "reqHeader."))
+}
+
+func TestRealIP(t *testing.T) {
+	opt := GetGinOpt()
+	req.NoError(t, opt.SetTrustedProxies([]string{"10.0.0.0/8"}))
+	tests := []struct {
+		name   string
+		remote string
+		xff    string
+		want   string
+	}{
+		{"noHeader", "10.0.0.1:5000", "", "10.0.0.1"},
+		{"untrustedRemote", "203.0.113.7:5000", "1.2.3.4", "203.0.113.7"},
+		{"trustedChain", "10.0.0.1:5000", "198.51.100.9, 10.1.1.1", "198.51.100.9"},
+		{"allTrusted", "10.0.0.1:5000", "10.0.0.2, 10.0.0.3", "10.0.0.2"},
+		//proxy 10.0.0.3 is never taken as client
+		{"garbageHop", "10.0.0.1:5000", "198.51.100.9, bad, 10.0.0.3", "10.0.0.1"},
+		{"garbageLeft", "10.0.0.1:5000", "bad, 198.51.100.9, 10.0.0.3", "198.51.100.9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.RemoteAddr = tt.remote
+			if tt.xff != "" {
+				request.Header.Set("X-Forwarded-For", tt.xff)
+			}
+			req.Equal(t, tt.want, opt.realIP(request))
+		})
+	}
+}
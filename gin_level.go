@@ -0,0 +1,86 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 22:25:30
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 22:25:30
+ * @FilePath: /xlogrus/gin_level.go
+ * @Description: status code to log level mapping for gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	c "github.com/justin-ren/xlogrus/common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+/*StatusLevel
+ * @msg log level for status range, such as
+ *		{StatusRange: xlog.StatusCode(404), Level: logrus.InfoLevel},
+ *		limited to routes by RouteMatcher, all routes if it's empty
+ */
+type StatusLevel struct {
+	StatusRange
+	RouteMatcher
+	//debug, info, warn or error
+	Level logrus.Level
+}
+
+// SetStatusLevels sets mapping from status to log level, the first matched rule is used,
+// unmatched status is logged as error for 5xx, warn for 4xx and info for others.
+func (opt *GinOpt) SetStatusLevels(rules []StatusLevel) error {
+	//compile a copy, rules of caller may be shared
+	rules = append([]StatusLevel(nil), rules...)
+	for i := range rules {
+		rule := &rules[i]
+		if rule.From > rule.To {
+			return errors.Errorf("invalid status range %d-%d", rule.From, rule.To)
+		}
+		//fatal and panic would exit or break the request
+		if rule.Level < logrus.ErrorLevel || rule.Level > logrus.TraceLevel {
+			return errors.Errorf("invalid level %q for status %d-%d", rule.Level, rule.From, rule.To)
+		}
+		if !rule.RouteMatcher.empty() {
+			if err := rule.compile(); err != nil {
+				return err
+			}
+		}
+	}
+	opt.StatusLevels = rules
+	return nil
+}
+
+// WithStatusLevels 设置状态码对应的日志级别, 支持单个状态码, 范围和路由
+func WithStatusLevels[
+	T any,
+	PT interface {
+		*T
+		SetStatusLevels([]StatusLevel) error
+	},
+](rules []StatusLevel) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetStatusLevels(rules)
+	})
+}
+
+// statusLevel returns log level for status of request
+func (opt *GinOpt) statusLevel(ctx *gin.Context, status int) logrus.Level {
+	for i := range opt.StatusLevels {
+		//empty matcher matches all routes
+		if rule := &opt.StatusLevels[i]; rule.Contains(status) && rule.Match(ctx) {
+			return rule.Level
+		}
+	}
+	switch {
+	case status >= http.StatusInternalServerError: //500 assign to error level
+		return logrus.ErrorLevel
+	case status >= http.StatusBadRequest: //400 assign to warn level
+		return logrus.WarnLevel
+	}
+	return logrus.InfoLevel
+}
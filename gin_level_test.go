@@ -0,0 +1,75 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 22:41:02
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 22:41:02
+ * @FilePath: /xlogrus/gin_level_test.go
+ * @Description: test status code to log level mapping
+ *
+ */
+
+package xlogrus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	req "github.com/stretchr/testify/require" //exit if failed
+)
+
+func TestGinStatusLevels(t *testing.T) {
+	_, _, _, err := NewGinLog(WithStatusLevels[GinOpt]([]StatusLevel{{StatusRange: StatusCode(404)}}))
+	req.Error(t, err, "panic level is invalid")
+	_, _, _, err = NewGinLog(WithStatusLevels[GinOpt]([]StatusLevel{{StatusRange: StatusRange{500, 400}, Level: logrus.InfoLevel}}))
+	req.Error(t, err)
+	_, _, _, err = NewGinLog(WithStatusLevels[GinOpt]([]StatusLevel{
+		{StatusRange: StatusCode(404), RouteMatcher: RouteMatcher{Regex: "("}, Level: logrus.InfoLevel}}))
+	req.Error(t, err)
+
+	r, hook, _ := newTestGin(t, WithStatusLevels[GinOpt]([]StatusLevel{
+		{StatusRange: StatusCode(404), RouteMatcher: RouteMatcher{Glob: "/admin/**"}, Level: logrus.ErrorLevel},
+		{StatusRange: StatusCode(404), Level: logrus.InfoLevel},
+		{StatusRange: StatusCode(409), RouteMatcher: RouteMatcher{Method: "POST", Regex: `^/(admin/)?status$`},
+			Level: logrus.ErrorLevel},
+		{StatusRange: StatusRange{401, 403}, Level: logrus.WarnLevel},
+		{StatusRange: StatusCode(429), Level: logrus.InfoLevel},
+		{StatusRange: StatusCode(499), Level: logrus.DebugLevel},
+	}))
+	status := func(ctx *gin.Context) {
+		code, _ := strconv.Atoi(ctx.Query("code"))
+		ctx.Status(code)
+	}
+	r.GET("/status", status)
+	r.GET("/admin/status", status)
+	r.POST("/status", status)
+
+	tests := []struct {
+		path string
+		want logrus.Level
+	}{
+		{"/status?code=404", logrus.InfoLevel},
+		{"/status?code=409", logrus.WarnLevel},
+		{"/admin/status?code=404", logrus.ErrorLevel},
+		{"/missing", logrus.InfoLevel},
+		{"/status?code=403", logrus.WarnLevel},
+		{"/status?code=429", logrus.InfoLevel},
+		{"/status?code=499", logrus.DebugLevel},
+		//default mapping for others
+		{"/status?code=400", logrus.WarnLevel},
+		{"/status?code=503", logrus.ErrorLevel},
+		{"/status?code=204", logrus.InfoLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+			req.Equal(t, tt.want, hook.LastEntry().Level)
+		})
+	}
+	//method and regex of route matcher
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/status?code=409", nil))
+	req.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+}
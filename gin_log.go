@@ -10,7 +10,7 @@
 package xlogrus
 
 import (
-	"net/http"
+	"net"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -21,12 +21,79 @@ import (
 
 type GinOpt struct {
 	*c.OptLog
+	//skip access log for exact path
 	SkipRoute map[string]struct{}
+	//skip access log by rules
+	SkipRules []SkipRule
+	//generator of request id, uuid or ulid, disabled if empty
+	RequestIDGen string
+	//header to read and echo request id
+	RequestIDHeader string
+	//parse W3C traceparent and log traceId/spanId/parentSpanId
+	TracePropagation bool
+	//capture request body, disabled if nil
+	RequestBody *BodyCapture
+	//capture response body, disabled if nil
+	ResponseBody *BodyCapture
+	//request headers logged as reqHeader.<name>
+	RequestHeaders []string
+	//response headers logged as respHeader.<name>
+	ResponseHeaders []string
+	//headers logged as *** such as Authorization
+	MaskHeaders map[string]struct{}
+	//masked by default but logged in plain text
+	UnmaskHeaders map[string]struct{}
+	//max length of header value
+	HeaderMaxLen int
+	//query string in path, keep, drop or redact
+	QueryMode string
+	//format of access.log, text, common or combined
+	AccessLogFormat string
+	//append latency in microseconds to common/combined format
+	AccessLogLatency bool
+	//requests slower than it are logged at warn level at least, disabled if 0
+	SlowThreshold time.Duration
+	//threshold per route
+	SlowRules []SlowRule
+	//log level by status and route, the first matched one is used
+	StatusLevels []StatusLevel
+	//log level by gin error type of ctx.Errors
+	ErrorLevels map[gin.ErrorType]logrus.Level
+	//client ip mode, full, truncate or hash
+	ClientIPMode string
+	//key of HMAC for hash mode
+	IPHashKey []byte
+	//proxies whose X-Forwarded-For is trusted, gin ClientIP() is used if empty
+	TrustedProxies []*net.IPNet
+	//log X-Forwarded-For chain as forwardedFor
+	LogForwardedFor bool
+	//log ua.* fields parsed from User-Agent
+	LogUserAgent bool
+	//LRU cache size of parsed user agents, 0 to parse without cache
+	UserAgentCache int
+	//policy for bots and health checkers, log, downgrade or skip
+	BotPolicy string
+	//extra user agent tokens of bots
+	BotPatterns []string
+	//logger of entry returned by GinLogger(ctx), gin logger if nil
+	RequestLogger *TLogrus
+
+	uaParser *UserAgentParser
 }
 
 func GetGinOpt() *GinOpt {
 	opt := GinOpt{OptLog: c.InitOpt()}
 	opt.FileNamePrefix = "access.log"
+	opt.LoggerName = "gin"
+	opt.RequestIDHeader = "X-Request-ID"
+	_ = opt.SetMaskHeaders(defaultMaskHeaders)
+	opt.HeaderMaxLen = 256
+	opt.QueryMode = QueryKeep
+	opt.AccessLogFormat = AccessLogText
+	_ = opt.SetErrorLevels(nil)
+	opt.ClientIPMode = IPFull
+	opt.UserAgentCache = 1024
+	opt.BotPolicy = BotLog
 
 	return &opt
 }
@@ -59,53 +126,85 @@ func NewGinLog(setFunc ...c.LogOption[GinOpt]) (*TLogrus, gin.HandlerFunc, *GinO
 		}
 	}
 
+	if err := opt.checkClientIP(); err != nil {
+		return nil, nil, nil, err
+	}
+	opt.initUserAgent()
+	//common/combined format is for access.log only
+	if f := opt.clfFormatter(); f != nil {
+		opt.FileFormatter = f
+	}
 	if log, err := opt.ConfigLogrus(); err != nil {
 		return log, nil, nil, errors.Cause(err)
 	} else {
 		return log,
 			func(ctx *gin.Context) {
-				if _, ok := opt.SkipRoute[ctx.Request.URL.Path]; ok {
-					return
-				}
+				requestID := opt.setRequestID(ctx)
+				traceFields := opt.startSpan(ctx)
 				start := time.Now()
 				path := ctx.Request.URL.Path
 				raw := ctx.Request.URL.RawQuery
+				clientIP := opt.clientIP(ctx)
+				opt.injectLogger(ctx, log, clientIP)
+				reqBody := opt.captureRequestBody(ctx)
+				writer := opt.wrapWriter(ctx, start)
 				ctx.Next()
 				end := time.Now()
 				latency := end.Sub(start) //记录请求处理时间
-				clientIP := ctx.ClientIP()
 				method := ctx.Request.Method
 				statusCode := ctx.Writer.Status()
+				ua := opt.userAgent(ctx)
+				if opt.skipped(ctx, statusCode) || opt.botSkipped(ctx, ua) {
+					return
+				}
 				//请求大小
 				bodySize := ctx.Writer.Size()
 
 				//记录url param
-				if raw != "" {
-					path = path + "?" + raw
-				}
-				//设置json字段内容
-				entry := log.WithFields(logrus.Fields{
+				path = opt.pathWithQuery(path, raw)
+				//设置json字段内容, fields added by WithLogger in handler are kept
+				entry := log.WithContext(withAccessEntry(ctx.Request.Context(), start)).
+					WithFields(fieldsFromContext(ctx.Request.Context())).WithFields(logrus.Fields{
 					"statusCode": statusCode,
 					"latency":    latency, // time to process
 					"clientIP":   clientIP,
 					"method":     method,
 					"path":       path,
+					"route":      ctx.FullPath(), //route template such as /users/:id
+					"handler":    ctx.HandlerName(),
 					"dataLength": bodySize,
 				})
+				if requestID != "" {
+					entry = entry.WithField("requestID", requestID)
+				}
+				entry = entry.WithFields(traceFields).
+					WithFields(opt.requestBodyFields(reqBody, statusCode)).
+					WithFields(opt.responseBodyFields(writer, statusCode)).
+					WithFields(opt.headerFields("reqHeader.", opt.RequestHeaders, ctx.Request.Header)).
+					WithFields(opt.headerFields("respHeader.", opt.ResponseHeaders, ctx.Writer.Header())).
+					WithFields(opt.clfFields(ctx)).
+					WithFields(opt.forwardedFields(ctx)).
+					WithFields(opt.userAgentFields(ua)).
+					WithFields(writer.timingFields(latency))
 
+				//base on response value to match log level
+				level, msg := opt.statusLevel(ctx, statusCode), ""
 				if len(ctx.Errors) > 0 {
-					entry.Error(ctx.Errors.ByType(gin.ErrorTypePrivate).String())
-				} else {
-					//base on response value to match log level
-					//msg := fmt.Sprintf("%s - \"%s %s\" %d %d (%dms)", clientIP, method, path, statusCode, bodySize, latency)
-					if statusCode >= http.StatusInternalServerError { //500 assign to error level
-						entry.Error()
-					} else if statusCode >= http.StatusBadRequest { //400 assign to warn level
-						entry.Warn()
-					} else {
-						entry.Info()
+					fields, errLevel, errMsg := opt.errorsEntry(ctx.Errors)
+					entry, msg = entry.WithFields(fields), errMsg
+					if errLevel < level {
+						level = errLevel
+					}
+				}
+				level = opt.botLevel(ua, level)
+				//slow request goes to error.log even if it's 2xx
+				if slow := opt.slowFields(ctx, latency); slow != nil {
+					entry = entry.WithFields(slow)
+					if level > logrus.WarnLevel {
+						level = logrus.WarnLevel
 					}
 				}
+				entry.Log(level, msg)
 			},
 			opt,
 			nil
@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	c "github.com/justin-ren/xlogrus/common"
 	"github.com/sirupsen/logrus"
 
 	"github.com/itchyny/timefmt-go"               //convert golang time layout to linux time layout
@@ -78,6 +79,22 @@ func ginLogHandle(t *testing.T, tr *testRoute, opt *GinOpt, r *gin.Engine,
 		req.NotContains(t, string(lContent), tr.url)
 	}
 }
+
+// newTestGin creates gin engine with access log discarding stdout
+func newTestGin(t *testing.T, setFunc ...c.LogOption[GinOpt]) (*gin.Engine, *lTest.Hook, *GinOpt) {
+	t.Helper()
+	setFunc = append([]c.LogOption[GinOpt]{WithLogPath[GinOpt](t.TempDir() + "/")}, setFunc...)
+	lg, handler, opt, err := NewGinLog(setFunc...)
+	req.NoError(t, err)
+	t.Cleanup(func() { opt.Close() })
+	lg.SetOutput(io.Discard)
+	hook := lTest.NewLocal(lg)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(handler)
+	return r, hook, opt
+}
+
 func TestGinLog(t *testing.T) {
 
 	tests := []testRoute{
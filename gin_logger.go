@@ -0,0 +1,72 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 12:40:18
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 12:40:18
+ * @FilePath: /xlogrus/gin_logger.go
+ * @Description: request-scoped logger in gin context
+ *
+ */
+
+package xlogrus
+
+import (
+	"github.com/gin-gonic/gin"
+	c "github.com/justin-ren/xlogrus/common"
+	"github.com/sirupsen/logrus"
+)
+
+// SetRequestLogger sets logger for request-scoped entry, nil to use gin logger
+// or logger set by outer middleware.
+func (opt *GinOpt) SetRequestLogger(lg *TLogrus) error {
+	opt.RequestLogger = lg
+	return nil
+}
+
+// WithRequestLogger 设置handler中GinLogger(ctx)使用的logger, 默认为gin logger
+func WithRequestLogger[
+	T any,
+	PT interface {
+		*T
+		SetRequestLogger(*TLogrus) error
+	},
+](lg *TLogrus) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetRequestLogger(lg)
+	})
+}
+
+/*injectLogger
+ * @msg put request-scoped entry into request context, it carries fields added
+ *		before such as requestID and traceId, plus method, route and clientIP.
+ *		logger is RequestLogger, or logger set by outer middleware with WithLogger,
+ *		or gin logger
+ * @receiver opt
+ * @param ctx
+ * @param lg gin logger
+ * @param clientIP
+ */
+func (opt *GinOpt) injectLogger(ctx *gin.Context, lg *TLogrus, clientIP string) {
+	if outer := entryFromContext(ctx.Request.Context()); outer != nil && outer.Logger != nil {
+		lg = outer.Logger
+	}
+	if opt.RequestLogger != nil {
+		lg = opt.RequestLogger
+	}
+	entry := lg.WithFields(fieldsFromContext(ctx.Request.Context())).WithFields(logrus.Fields{
+		"method":   ctx.Request.Method,
+		"route":    ctx.FullPath(),
+		"clientIP": clientIP,
+	})
+	WithLogger(ctx, entry)
+}
+
+/*GinLogger
+ * @msg request-scoped entry in handlers, such as xlog.GinLogger(ctx).Info("created"),
+ *		it has requestID, method, route and clientIP set by gin middleware
+ * @param ctx
+ * @return: *logrus.Entry entry of logrus standard logger if middleware is not used
+ */
+func GinLogger(ctx *gin.Context) *logrus.Entry {
+	return FromContext(ctx)
+}
@@ -0,0 +1,64 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 12:58:03
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 12:58:03
+ * @FilePath: /xlogrus/gin_logger_test.go
+ * @Description: test request-scoped logger in gin context
+ *
+ */
+
+package xlogrus
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	lTest "github.com/sirupsen/logrus/hooks/test" //logrus tools for test
+	req "github.com/stretchr/testify/require"     //exit if failed
+)
+
+func createOrder(ctx *gin.Context) {
+	GinLogger(ctx).WithField("orderID", 7).Info("order created")
+	ctx.Status(http.StatusCreated)
+}
+
+func TestGinLogger(t *testing.T) {
+	//no middleware
+	gin.SetMode(gin.TestMode)
+	gCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	gCtx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Equal(t, logrus.StandardLogger(), GinLogger(gCtx).Logger)
+
+	r, hook, _ := newTestGin(t, WithRequestID[GinOpt](RequestIDUUID))
+	r.POST("/users/:id/orders", createOrder)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1/orders", nil))
+
+	entries := hook.AllEntries()
+	req.Len(t, entries, 2)
+	handlerEntry, access := entries[0], entries[1]
+	req.Equal(t, "order created", handlerEntry.Message)
+	req.Equal(t, w.Header().Get("X-Request-ID"), handlerEntry.Data["requestID"])
+	for _, k := range []string{"requestID", "method", "route", "clientIP"} {
+		req.Equal(t, access.Data[k], handlerEntry.Data[k], k)
+	}
+	req.Equal(t, "/users/:id/orders", handlerEntry.Data["route"])
+	//fields of handler entry are not added to access log
+	req.NotContains(t, access.Data, "orderID")
+
+	//user logger for handlers
+	userLg := logrus.New()
+	userLg.SetOutput(io.Discard)
+	userHook := lTest.NewLocal(userLg)
+	r, hook, _ = newTestGin(t, WithRequestLogger[GinOpt](userLg))
+	r.POST("/users/:id/orders", createOrder)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users/1/orders", nil))
+	req.Len(t, hook.AllEntries(), 1)
+	req.Equal(t, "order created", userHook.LastEntry().Message)
+	req.Equal(t, "POST", userHook.LastEntry().Data["method"])
+}
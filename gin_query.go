@@ -0,0 +1,77 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 23:52:18
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 23:52:18
+ * @FilePath: /xlogrus/gin_query.go
+ * @Description: query string of path in gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"net/url"
+	"strings"
+
+	c "github.com/justin-ren/xlogrus/common"
+	"github.com/pkg/errors"
+)
+
+// mode of query string in path field
+const (
+	QueryKeep   = "keep"   //path?a=1&b=2
+	QueryDrop   = "drop"   //path
+	QueryRedact = "redact" //path?a=***&b=***
+)
+
+// SetQueryMode sets mode of query string in path field, keep, drop or redact.
+func (opt *GinOpt) SetQueryMode(mode string) error {
+	switch mode {
+	case QueryKeep, QueryDrop, QueryRedact:
+		opt.QueryMode = mode
+		return nil
+	}
+	return errors.Errorf("invalid query mode %q", mode)
+}
+
+// WithQueryMode 设置path字段中query string的记录方式: keep, drop, redact
+func WithQueryMode[
+	T any,
+	PT interface {
+		*T
+		SetQueryMode(string) error
+	},
+](mode string) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetQueryMode(mode)
+	})
+}
+
+// pathWithQuery appends query string to path by QueryMode
+func (opt *GinOpt) pathWithQuery(path, raw string) string {
+	if raw == "" || opt.QueryMode == QueryDrop {
+		return path
+	}
+	if opt.QueryMode == QueryRedact {
+		values, err := url.ParseQuery(raw)
+		if err != nil {
+			return path + "?" + redactRawQuery(raw)
+		}
+		return path + "?" + encodeMasked(values, func(string) bool { return true })
+	}
+	return path + "?" + raw
+}
+
+// redactRawQuery keeps raw keys of query which is not parsed, such as a=1;b=2&c=%zz
+func redactRawQuery(raw string) string {
+	parts := strings.Split(raw, "&")
+	keys := make([]string, 0, len(parts))
+	for _, part := range parts {
+		key, _, _ := strings.Cut(part, "=")
+		if key != "" {
+			keys = append(keys, key+"="+redactedValue)
+		}
+	}
+	return strings.Join(keys, "&")
+}
@@ -0,0 +1,62 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 23:58:40
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 23:58:40
+ * @FilePath: /xlogrus/gin_query_test.go
+ * @Description: test route, handler and query mode of gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	req "github.com/stretchr/testify/require" //exit if failed
+)
+
+func getUser(ctx *gin.Context) {
+	ctx.String(http.StatusOK, "ok")
+}
+
+func TestGinRouteAndQuery(t *testing.T) {
+	_, _, _, err := NewGinLog(WithQueryMode[GinOpt]("hide"))
+	req.Error(t, err)
+
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{QueryKeep, "/users/12?b=2&a=1"},
+		{QueryDrop, "/users/12"},
+		{QueryRedact, "/users/12?a=***&b=***"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			r, hook, _ := newTestGin(t, WithQueryMode[GinOpt](tt.mode))
+			r.GET("/users/:id", getUser)
+
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/12?b=2&a=1", nil))
+			entry := hook.LastEntry()
+			req.NotNil(t, entry)
+			req.Equal(t, tt.want, entry.Data["path"])
+			req.Equal(t, "/users/:id", entry.Data["route"])
+			req.Contains(t, entry.Data["handler"], "getUser")
+		})
+	}
+
+	//keys are kept without decoding if query is not parsed
+	r, hook, _ := newTestGin(t, WithQueryMode[GinOpt](QueryRedact))
+	r.GET("/users/:id", getUser)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/12?a=1;b=2&c%20d=%zz&&e", nil))
+	req.Equal(t, "/users/12?a=***&c%20d=***&e=***", hook.LastEntry().Data["path"])
+
+	//route is empty if no route matched
+	r, hook, _ = newTestGin(t)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+	req.Equal(t, "", hook.LastEntry().Data["route"])
+}
@@ -0,0 +1,219 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 21:05:37
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 21:05:37
+ * @FilePath: /xlogrus/gin_recovery.go
+ * @Description: gin panic recovery logged by xlogrus
+ *
+ */
+
+package xlogrus
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+	c "github.com/justin-ren/xlogrus/common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type RecoveryOpt struct {
+	//json body of 500 response
+	Body interface{}
+	//write response by itself instead of Body, such as ctx.AbortWithStatusJSON
+	Handler func(ctx *gin.Context, recovered interface{})
+	//max frames of stack
+	StackDepth int
+}
+
+func GetRecoveryOpt() *RecoveryOpt {
+	return &RecoveryOpt{
+		Body:       gin.H{"error": http.StatusText(http.StatusInternalServerError)},
+		StackDepth: 32,
+	}
+}
+
+// SetRecoveryBody sets json body of 500 response, nil for empty body.
+func (o *RecoveryOpt) SetRecoveryBody(body interface{}) error {
+	o.Body = body
+	return nil
+}
+
+// SetRecoveryHandler sets function to write response after panic.
+func (o *RecoveryOpt) SetRecoveryHandler(fn func(ctx *gin.Context, recovered interface{})) error {
+	o.Handler = fn
+	return nil
+}
+
+// SetStackDepth sets max frames of stack.
+func (o *RecoveryOpt) SetStackDepth(depth int) error {
+	if depth <= 0 {
+		return errors.New("stack depth must be positive")
+	}
+	o.StackDepth = depth
+	return nil
+}
+
+// WithRecoveryBody 设置panic后500响应的json内容
+func WithRecoveryBody[
+	T any,
+	PT interface {
+		*T
+		SetRecoveryBody(interface{}) error
+	},
+](body interface{}) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetRecoveryBody(body)
+	})
+}
+
+// WithRecoveryHandler 设置panic后自定义的响应函数
+func WithRecoveryHandler[
+	T any,
+	PT interface {
+		*T
+		SetRecoveryHandler(func(*gin.Context, interface{})) error
+	},
+](fn func(ctx *gin.Context, recovered interface{})) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetRecoveryHandler(fn)
+	})
+}
+
+// WithStackDepth 设置记录的最大调用栈深度
+func WithStackDepth[
+	T any,
+	PT interface {
+		*T
+		SetStackDepth(int) error
+	},
+](depth int) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetStackDepth(depth)
+	})
+}
+
+/*NewGinRecovery
+ * @msg recover panic in handlers and log it at error level with stack,
+ *		use it after access log middleware so access log records status 500
+ *		and client ip is anonymized as access log: r.Use(ginMiddleware, recovery)
+ * @param lg logger such as the one from NewGinLog
+ * @param setFunc
+ * @return: gin.HandlerFunc
+ * @return: error
+ */
+func NewGinRecovery(lg *TLogrus, setFunc ...c.LogOption[RecoveryOpt]) (gin.HandlerFunc, error) {
+	if lg == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	opt := GetRecoveryOpt()
+	for _, f := range setFunc {
+		if err := f.Apply(opt); err != nil {
+			return nil, errors.Wrap(err, "failed to apply recovery option")
+		}
+	}
+	return func(ctx *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			//http.ErrAbortHandler is used to abort response silently
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+			brokenPipe := isBrokenPipe(recovered)
+			reqCtx := ctx.Request.Context()
+			entry := lg.WithContext(reqCtx).WithFields(fieldsFromContext(reqCtx)).WithFields(logrus.Fields{
+				"panic":    fmt.Sprint(recovered),
+				"stack":    panicStack(opt.StackDepth),
+				"method":   ctx.Request.Method,
+				"path":     ctx.Request.URL.Path,
+				"clientIP": loggedClientIP(ctx), //anonymized by access log middleware
+			})
+			if id := RequestIDFromContext(reqCtx); id != "" {
+				entry = entry.WithField("requestID", id)
+			}
+			if brokenPipe {
+				//client is gone, nothing can be written
+				entry.WithField("brokenPipe", true).Error("connection broken")
+				ctx.Abort()
+				return
+			}
+			entry.Error("panic recovered")
+
+			switch {
+			case opt.Handler != nil:
+				opt.Handler(ctx, recovered)
+				//handler may not write anything
+				if ctx.Writer.Written() {
+					ctx.Abort()
+				} else {
+					ctx.AbortWithStatus(http.StatusInternalServerError)
+				}
+			case opt.Body != nil:
+				ctx.AbortWithStatusJSON(http.StatusInternalServerError, opt.Body)
+			default:
+				ctx.AbortWithStatus(http.StatusInternalServerError)
+			}
+		}()
+		ctx.Next()
+	}, nil
+}
+
+/*panicStack
+ * @msg frames of panic such as "main.handler /app/main.go:12",
+ *		frames of recover and runtime.gopanic are skipped
+ * @param depth max frames
+ * @return: []string
+ */
+func panicStack(depth int) []string {
+	pcs := make([]uintptr, depth+16)
+	n := runtime.Callers(1, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	var stack []string
+	found := false
+	for {
+		frame, more := frames.Next()
+		if found {
+			stack = append(stack, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+			if len(stack) >= depth {
+				break
+			}
+		} else if frame.Function == "runtime.gopanic" {
+			found = true
+		}
+		if !more {
+			break
+		}
+	}
+	return stack
+}
+
+// isBrokenPipe reports whether panic is caused by closed client connection
+func isBrokenPipe(recovered interface{}) bool {
+	err, ok := recovered.(error)
+	if !ok {
+		return false
+	}
+	if errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		var sysErr *os.SyscallError
+		if errors.As(opErr, &sysErr) {
+			msg := strings.ToLower(sysErr.Error())
+			return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 21:32:09
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 21:32:09
+ * @FilePath: /xlogrus/gin_recovery_test.go
+ * @Description: test panic recovery middleware
+ *
+ */
+
+package xlogrus
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	c "github.com/justin-ren/xlogrus/common"
+	"github.com/sirupsen/logrus"
+	lTest "github.com/sirupsen/logrus/hooks/test" //logrus tools for test
+	req "github.com/stretchr/testify/require"     //exit if failed
+)
+
+func panicHandler(ctx *gin.Context) {
+	panic("boom")
+}
+
+func TestGinRecovery(t *testing.T) {
+	_, err := NewGinRecovery(nil)
+	req.Error(t, err)
+	_, err = NewGinRecovery(logrus.New(), WithStackDepth[RecoveryOpt](0))
+	req.Error(t, err)
+
+	tests := []struct {
+		name     string
+		setFunc  []c.LogOption[RecoveryOpt]
+		wantBody string
+	}{
+		{"defaultBody", nil, `{"error":"Internal Server Error"}`},
+		{"customBody", []c.LogOption[RecoveryOpt]{WithRecoveryBody[RecoveryOpt](gin.H{"code": 1})}, `{"code":1}`},
+		{"noBody", []c.LogOption[RecoveryOpt]{WithRecoveryBody[RecoveryOpt](nil)}, ""},
+		{"handler", []c.LogOption[RecoveryOpt]{WithRecoveryHandler[RecoveryOpt](func(ctx *gin.Context, recovered interface{}) {
+			ctx.String(http.StatusServiceUnavailable, "%v", recovered)
+		})}, "boom"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lg, handler, opt, err := NewGinLog(WithLogPath[GinOpt](t.TempDir()+"/"), WithRequestID[GinOpt](RequestIDUUID))
+			req.NoError(t, err)
+			defer opt.Close()
+			lg.SetOutput(io.Discard)
+			hook := lTest.NewLocal(lg)
+			//recovery logs to gin logger too
+			recovery, err := NewGinRecovery(lg, tt.setFunc...)
+			req.NoError(t, err)
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(handler, recovery)
+			r.GET("/panic", panicHandler)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+			req.Equal(t, tt.wantBody, w.Body.String())
+
+			entries := hook.AllEntries()
+			req.Len(t, entries, 2)
+			panicEntry, access := entries[0], entries[1]
+			req.Equal(t, logrus.ErrorLevel, panicEntry.Level)
+			req.Equal(t, "boom", panicEntry.Data["panic"])
+			req.Equal(t, "GET", panicEntry.Data["method"])
+			req.Equal(t, "/panic", panicEntry.Data["path"])
+			req.NotEmpty(t, panicEntry.Data["clientIP"])
+			req.Equal(t, w.Header().Get("X-Request-ID"), panicEntry.Data["requestID"])
+			stack := panicEntry.Data["stack"].([]string)
+			req.NotEmpty(t, stack)
+			req.True(t, strings.HasPrefix(stack[0], "github.com/justin-ren/xlogrus.panicHandler "), stack[0])
+
+			//access log still records the response status
+			req.Equal(t, w.Code, access.Data["statusCode"])
+			req.Equal(t, logrus.ErrorLevel, access.Level)
+		})
+	}
+}
+
+func TestIsBrokenPipe(t *testing.T) {
+	req.True(t, isBrokenPipe(os.NewSyscallError("write", syscall.EPIPE)))
+	req.False(t, isBrokenPipe(io.EOF))
+	req.False(t, isBrokenPipe("boom"))
+}
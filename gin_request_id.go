@@ -0,0 +1,161 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 18:30:22
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 18:30:22
+ * @FilePath: /xlogrus/gin_request_id.go
+ * @Description: request id for gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	c "github.com/justin-ren/xlogrus/common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// generator of request id
+const (
+	RequestIDUUID = "uuid" //UUIDv4
+	RequestIDULID = "ulid"
+)
+
+// max length of request id from client, new one is generated if it's longer
+const maxRequestIDLength = 128
+
+// SetRequestID sets generator of request id, "uuid" or "ulid", "" to disable request id.
+func (opt *GinOpt) SetRequestID(gen string) error {
+	switch gen {
+	case "", RequestIDUUID, RequestIDULID:
+		opt.RequestIDGen = gen
+		return nil
+	}
+	return errors.Errorf("invalid request id generator %q", gen)
+}
+
+// SetRequestIDHeader sets header to read and echo request id.
+func (opt *GinOpt) SetRequestIDHeader(header string) error {
+	if header == "" {
+		return errors.New("request id header cannot be empty")
+	}
+	opt.RequestIDHeader = header
+	return nil
+}
+
+// WithRequestID 设置request id的生成方式, uuid或ulid
+func WithRequestID[
+	T any,
+	PT interface {
+		*T
+		SetRequestID(string) error
+	},
+](gen string) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetRequestID(gen)
+	})
+}
+
+// WithRequestIDHeader 设置request id的请求头，默认X-Request-ID
+func WithRequestIDHeader[
+	T any,
+	PT interface {
+		*T
+		SetRequestIDHeader(string) error
+	},
+](header string) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetRequestIDHeader(header)
+	})
+}
+
+/*setRequestID
+ * @msg read request id from header or generate one, then echo it in response,
+ *		set it to gin ctx and request context for user and gorm logs
+ * @receiver opt
+ * @param ctx
+ * @return: string request id, empty if disabled
+ */
+func (opt *GinOpt) setRequestID(ctx *gin.Context) string {
+	if opt.RequestIDGen == "" {
+		return ""
+	}
+	id := ctx.GetHeader(opt.RequestIDHeader)
+	if !validRequestID(id) {
+		id = newRequestID(opt.RequestIDGen)
+	}
+	ctx.Header(opt.RequestIDHeader, id)
+	ctx.Set("requestID", id)
+	ctx.Request = ctx.Request.WithContext(ContextWithRequestID(ctx.Request.Context(), id))
+	AddFields(ctx, logrus.Fields{"requestID": id})
+	return id
+}
+
+// validRequestID accepts printable ascii only to avoid log injection
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID(gen string) string {
+	if gen == RequestIDULID {
+		return NewULID()
+	}
+	return NewUUID()
+}
+
+// NewUUID returns random UUIDv4 such as 9b2f6a4e-1c3d-4f5a-8b6c-7d8e9f0a1b2c
+func NewUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40 //version 4
+	b[8] = b[8]&0x3f | 0x80 //variant 10
+	var s [36]byte
+	hex.Encode(s[0:8], b[0:4])
+	s[8] = '-'
+	hex.Encode(s[9:13], b[4:6])
+	s[13] = '-'
+	hex.Encode(s[14:18], b[6:8])
+	s[18] = '-'
+	hex.Encode(s[19:23], b[8:10])
+	s[23] = '-'
+	hex.Encode(s[24:], b[10:])
+	return string(s[:])
+}
+
+// crockford base32 for ULID
+const ulidEncoding = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewULID returns ULID with 48-bit millisecond timestamp and 80-bit randomness
+func NewULID() string {
+	var b [16]byte
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(time.Now().UnixMilli()))
+	copy(b[:6], ms[2:])
+	_, _ = rand.Read(b[6:])
+
+	//128 bits to 26 chars, the first char takes 3 bits
+	var s [26]byte
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+	for i := 25; i >= 0; i-- {
+		s[i] = ulidEncoding[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(s[:])
+}
@@ -0,0 +1,74 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 18:58:14
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 18:58:14
+ * @FilePath: /xlogrus/gin_request_id_test.go
+ * @Description: test request id in gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	lTest "github.com/sirupsen/logrus/hooks/test" //logrus tools for test
+	req "github.com/stretchr/testify/require"     //exit if failed
+)
+
+func TestGinRequestID(t *testing.T) {
+	gormLg, gormOpt, err := NewGormLog(WithLogPath[GormOpt](t.TempDir() + "/"))
+	req.NoError(t, err)
+	defer gormOpt.Close()
+	gormLg.Logger.SetOutput(io.Discard)
+	gormHook := lTest.NewLocal(gormLg.Logger)
+
+	tests := []struct {
+		name    string
+		gen     string
+		reqID   string
+		pattern string
+	}{
+		{"uuid", RequestIDUUID, "", `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{"ulid", RequestIDULID, "", `^[0-7][0-9A-HJKMNP-TV-Z]{25}$`},
+		{"fromClient", RequestIDUUID, "client-id-1", `^client-id-1$`},
+		{"invalidFromClient", RequestIDULID, "bad id\n", `^[0-7][0-9A-HJKMNP-TV-Z]{25}$`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, hook, _ := newTestGin(t, WithRequestID[GinOpt](tt.gen))
+			var ctxID string
+			r.GET("/order", func(ctx *gin.Context) {
+				ctxID = RequestIDFromContext(ctx.Request.Context())
+				gormLg.Warn(ctx.Request.Context(), "query")
+				ctx.Status(http.StatusOK)
+			})
+			request := httptest.NewRequest("GET", "/order", nil)
+			if tt.reqID != "" {
+				request.Header.Set("X-Request-ID", tt.reqID)
+			}
+			response := httptest.NewRecorder()
+			r.ServeHTTP(response, request)
+
+			id := response.Header().Get("X-Request-ID")
+			req.Regexp(t, regexp.MustCompile(tt.pattern), id)
+			req.Equal(t, id, ctxID)
+			req.Equal(t, id, hook.LastEntry().Data["requestID"])
+			req.Equal(t, id, gormHook.LastEntry().Data["requestID"])
+		})
+	}
+
+	//disabled by default
+	r, hook, _ := newTestGin(t)
+	r.GET("/order", func(ctx *gin.Context) {})
+	response := httptest.NewRecorder()
+	r.ServeHTTP(response, httptest.NewRequest("GET", "/order", nil))
+	req.Empty(t, response.Header().Get("X-Request-ID"))
+	req.NotContains(t, hook.LastEntry().Data, "requestID")
+}
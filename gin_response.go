@@ -0,0 +1,138 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 21:20:15
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 21:20:15
+ * @FilePath: /xlogrus/gin_response.go
+ * @Description: capture response body for gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	c "github.com/justin-ren/xlogrus/common"
+	"github.com/sirupsen/logrus"
+)
+
+// content types of response captured by default
+var defaultResponseTypes = []string{"application/json", "application/problem+json", "text/plain"}
+
+// SetResponseBody sets capture of response body, 5xx only if Statuses is empty.
+func (opt *GinOpt) SetResponseBody(capture BodyCapture) error {
+	if len(capture.Statuses) == 0 {
+		capture.Statuses = []StatusRange{StatusClass(5)}
+	}
+	if len(capture.ContentTypes) == 0 {
+		capture.ContentTypes = defaultResponseTypes
+	}
+	if err := capture.normalize(); err != nil {
+		return err
+	}
+	opt.ResponseBody = &capture
+	return nil
+}
+
+// WithResponseBody 设置记录响应体，默认只记录5xx
+func WithResponseBody[
+	T any,
+	PT interface {
+		*T
+		SetResponseBody(BodyCapture) error
+	},
+](capture BodyCapture) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetResponseBody(capture)
+	})
+}
+
+/*accessWriter
+ * @msg wrap gin.ResponseWriter to tee response body up to limit,
+ *		streaming response is not captured
+ */
+type accessWriter struct {
+	gin.ResponseWriter
+	//capture body if limit > 0
+	limit     int
+	body      bytes.Buffer
+	truncated bool
+	streaming bool
+
+	//time of request start, first byte and time spent in writing, see gin_timing.go
+	start     time.Time
+	firstByte time.Time
+	writing   time.Duration
+}
+
+func (w *accessWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	defer w.track(time.Now())
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *accessWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	defer w.track(time.Now())
+	return w.ResponseWriter.WriteString(s)
+}
+
+// WriteHeaderNow sends header, such as ctx.AbortWithStatus
+func (w *accessWriter) WriteHeaderNow() {
+	defer w.track(time.Now())
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Flush is called by streaming response, such as ctx.Stream or SSEvent
+func (w *accessWriter) Flush() {
+	w.streaming = true
+	defer w.track(time.Now())
+	w.ResponseWriter.Flush()
+}
+
+func (w *accessWriter) capture(data []byte) {
+	if w.limit <= 0 || w.streaming {
+		return
+	}
+	if strings.HasPrefix(w.Header().Get("Content-Type"), "text/event-stream") {
+		w.streaming = true
+		return
+	}
+	if rest := w.limit - w.body.Len(); len(data) > rest {
+		data, w.truncated = data[:rest], true
+	}
+	w.body.Write(data)
+}
+
+// wrapWriter replaces ctx.Writer with accessWriter
+func (opt *GinOpt) wrapWriter(ctx *gin.Context, start time.Time) *accessWriter {
+	w := &accessWriter{ResponseWriter: ctx.Writer, start: start}
+	if opt.ResponseBody != nil && opt.ResponseBody.matchRoute(ctx) {
+		w.limit = opt.ResponseBody.MaxBytes
+	}
+	ctx.Writer = w
+	return w
+}
+
+// responseBodyFields returns redacted response body if status and content type match
+func (opt *GinOpt) responseBodyFields(w *accessWriter, status int) logrus.Fields {
+	if w.limit <= 0 || w.streaming || w.body.Len() == 0 ||
+		!inStatusRanges(opt.ResponseBody.Statuses, status) {
+		return nil
+	}
+	contentType := w.Header().Get("Content-Type")
+	if !opt.ResponseBody.matchType(contentType) {
+		return nil
+	}
+	fields := logrus.Fields{
+		"respBody": redactBody(opt.ResponseBody.Keywords, contentType, w.body.Bytes()),
+	}
+	if w.truncated {
+		fields["respBodyTruncated"] = true
+	}
+	return fields
+}
@@ -0,0 +1,74 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 21:52:40
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 21:52:40
+ * @FilePath: /xlogrus/gin_response_test.go
+ * @Description: test response body capture in gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	req "github.com/stretchr/testify/require" //exit if failed
+)
+
+func TestGinResponseBody(t *testing.T) {
+	r, hook, _ := newTestGin(t, WithResponseBody[GinOpt](BodyCapture{
+		MaxBytes: 64,
+		Routes:   []string{"/users/:id", "/stream", "/long"},
+		Keywords: []BannedKeyword{{"token", false}},
+	}))
+	r.GET("/users/:id", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "db down", "token": "abc"})
+	})
+	r.GET("/ok/:id", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"id": ctx.Param("id")})
+	})
+	r.GET("/other", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "not captured"})
+	})
+	r.GET("/long", func(ctx *gin.Context) {
+		ctx.String(http.StatusBadGateway, strings.Repeat("x", 80))
+	})
+	r.GET("/stream", func(ctx *gin.Context) {
+		ctx.Header("Content-Type", "application/json")
+		ctx.Status(http.StatusInternalServerError)
+		_, _ = ctx.Writer.WriteString(`{"chunk":1}`)
+		ctx.Writer.Flush()
+		_, _ = ctx.Writer.WriteString(`{"chunk":2}`)
+	})
+
+	tests := []struct {
+		name      string
+		url       string
+		want      interface{}
+		truncated bool
+	}{
+		{"redacted", "/users/1", `{"error":"db down","token":"***"}`, false},
+		{"statusNotMatched", "/ok/1", nil, false},
+		{"routeNotMatched", "/other", nil, false},
+		{"truncated", "/long", strings.Repeat("x", 64), true},
+		{"streaming", "/stream", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			r.ServeHTTP(response, httptest.NewRequest("GET", tt.url, nil))
+			req.NotEmpty(t, response.Body.String())
+
+			data := hook.LastEntry().Data
+			req.Equal(t, tt.want, data["respBody"])
+			if tt.truncated {
+				req.Equal(t, true, data["respBodyTruncated"])
+			}
+		})
+	}
+}
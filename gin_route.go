@@ -0,0 +1,187 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 23:02:56
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 23:02:56
+ * @FilePath: /xlogrus/gin_route.go
+ * @Description: route matching rules for gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	c "github.com/justin-ren/xlogrus/common"
+	"github.com/pkg/errors"
+)
+
+/*RouteMatcher
+ * @msg match request by method and path, all non-empty fields must match,
+ *		Regex is compiled by Set functions such as SetSkipRules before requests,
+ *		it never matches if it's not compiled
+ */
+type RouteMatcher struct {
+	//such as GET, any method if empty
+	Method string
+	//path prefix such as /static/
+	Prefix string
+	//glob of path, * matches except '/', ** matches any, ? matches one char
+	Glob string
+	//regular expression of path
+	Regex string
+	//gin route template from ctx.FullPath(), such as /users/:id
+	Route string
+
+	re *regexp.Regexp
+}
+
+// empty reports whether no field is set
+func (m *RouteMatcher) empty() bool {
+	return m.Prefix == "" && m.Glob == "" && m.Regex == "" && m.Route == "" && m.Method == ""
+}
+
+// compile checks matcher and compiles Regex
+func (m *RouteMatcher) compile() error {
+	if m.empty() {
+		return errors.New("empty route matcher")
+	}
+	if m.Regex != "" {
+		re, err := regexp.Compile(m.Regex)
+		if err != nil {
+			return errors.Wrapf(err, "invalid route regex %q", m.Regex)
+		}
+		m.re = re
+	}
+	return nil
+}
+
+// Match reports whether request of ctx matches.
+func (m *RouteMatcher) Match(ctx *gin.Context) bool {
+	path := ctx.Request.URL.Path
+	if m.Method != "" && !strings.EqualFold(m.Method, ctx.Request.Method) ||
+		m.Prefix != "" && !strings.HasPrefix(path, m.Prefix) ||
+		m.Glob != "" && !matchGlob(m.Glob, path) ||
+		m.Route != "" && m.Route != ctx.FullPath() {
+		return false
+	}
+	if m.Regex != "" {
+		//not compiled by Set functions, it's read only while serving
+		return m.re != nil && m.re.MatchString(path)
+	}
+	return true
+}
+
+type SkipRule struct {
+	RouteMatcher
+	//skip only if status < BelowStatus such as 400, always skip if 0
+	BelowStatus int
+}
+
+// SetSkipRules sets rules to skip access log, errors of ctx.Errors are still logged.
+func (opt *GinOpt) SetSkipRules(rules []SkipRule) error {
+	//compile a copy, rules of caller may be shared
+	rules = append([]SkipRule(nil), rules...)
+	for i := range rules {
+		if err := rules[i].compile(); err != nil {
+			return err
+		}
+	}
+	opt.SkipRules = rules
+	return nil
+}
+
+// WithSkipRules 设置跳过记录的路由规则，支持前缀, glob, 正则, 方法和状态码
+func WithSkipRules[
+	T any,
+	PT interface {
+		*T
+		SetSkipRules([]SkipRule) error
+	},
+](rules []SkipRule) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetSkipRules(rules)
+	})
+}
+
+// skipped reports whether access log is skipped, it's never skipped with errors
+func (opt *GinOpt) skipped(ctx *gin.Context, status int) bool {
+	if len(ctx.Errors) > 0 {
+		return false
+	}
+	if _, ok := opt.SkipRoute[ctx.Request.URL.Path]; ok {
+		return true
+	}
+	for i := range opt.SkipRules {
+		rule := &opt.SkipRules[i]
+		if (rule.BelowStatus == 0 || status < rule.BelowStatus) && rule.Match(ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchRoutePattern reports whether route template or path matches glob pattern
+func matchRoutePattern(pattern string, ctx *gin.Context) bool {
+	return pattern == ctx.FullPath() || matchGlob(pattern, ctx.Request.URL.Path)
+}
+
+// matchRoutes reports whether any pattern matches, true if patterns is empty
+func matchRoutes(patterns []string, ctx *gin.Context) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if matchRoutePattern(p, ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+/*matchGlob
+ * @msg match path with glob pattern, such as /static/**, /users/*\/orders
+ *		* matches any chars except '/', ** matches any chars, ? matches one char except '/'
+ * @param pattern
+ * @param s
+ * @return: bool
+ */
+func matchGlob(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			if strings.HasPrefix(pattern, "**") {
+				rest := pattern[2:]
+				for i := 0; i <= len(s); i++ {
+					if matchGlob(rest, s[i:]) {
+						return true
+					}
+				}
+				return false
+			}
+			rest := pattern[1:]
+			for i := 0; i <= len(s); i++ {
+				if matchGlob(rest, s[i:]) {
+					return true
+				}
+				if i < len(s) && s[i] == '/' {
+					break
+				}
+			}
+			return false
+		case '?':
+			if len(s) == 0 || s[0] == '/' {
+				return false
+			}
+		default:
+			if len(s) == 0 || s[0] != pattern[0] {
+				return false
+			}
+		}
+		pattern, s = pattern[1:], s[1:]
+	}
+	return len(s) == 0
+}
@@ -0,0 +1,111 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 23:31:44
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 23:31:44
+ * @FilePath: /xlogrus/gin_route_test.go
+ * @Description: test skip rules and glob matching for gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	req "github.com/stretchr/testify/require" //exit if failed
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"/static/*", "/static/app.js", true},
+		{"/static/*", "/static/js/app.js", false},
+		{"/static/**", "/static/js/app.js", true},
+		{"/users/*/orders", "/users/12/orders", true},
+		{"/users/*/orders", "/users/12/items", false},
+		{"/health/?ive", "/health/live", true},
+		{"/health/?ive", "/health//ive", false},
+		{"/health", "/health/live", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+tt.path, func(t *testing.T) {
+			req.Equal(t, tt.want, matchGlob(tt.pattern, tt.path))
+		})
+	}
+}
+
+func TestGinSkipRules(t *testing.T) {
+	_, _, _, err := NewGinLog(WithSkipRules[GinOpt]([]SkipRule{{RouteMatcher: RouteMatcher{Regex: "("}}}))
+	req.Error(t, err)
+
+	//regex is compiled up front, matcher is not changed while serving
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/api/v1/ping", nil)
+	req.False(t, (&RouteMatcher{Regex: `^/api/`}).Match(ctx))
+	opt := GetGinOpt()
+	req.NoError(t, opt.SetSkipRules([]SkipRule{{RouteMatcher: RouteMatcher{Regex: `^/api/`}}}))
+	req.True(t, opt.SkipRules[0].Match(ctx))
+
+	r, hook, _ := newTestGin(t,
+		WithSkipRoute[GinOpt](map[string]struct{}{"/exact": {}}),
+		WithSkipRules[GinOpt]([]SkipRule{
+			{RouteMatcher: RouteMatcher{Prefix: "/health/"}},
+			{RouteMatcher: RouteMatcher{Glob: "/static/**"}, BelowStatus: 400},
+			{RouteMatcher: RouteMatcher{Method: "HEAD", Regex: `^/api/v\d+/ping$`}},
+			{RouteMatcher: RouteMatcher{Route: "/users/:id"}, BelowStatus: 400},
+		}),
+	)
+	handler := func(ctx *gin.Context) {
+		if ctx.Query("fail") != "" {
+			_ = ctx.Error(errors.New("failed"))
+		}
+		if ctx.Query("missing") != "" {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		ctx.Status(http.StatusOK)
+	}
+	for _, p := range []string{"/exact", "/health/live", "/static/js/app.js", "/users/:id", "/logged"} {
+		r.GET(p, handler)
+	}
+	r.HEAD("/api/v1/ping", handler)
+	r.GET("/api/v1/ping", handler)
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		isWant bool
+	}{
+		{"exact", "GET", "/exact", false},
+		{"exactWithError", "GET", "/exact?fail=1", true},
+		{"prefix", "GET", "/health/live", false},
+		{"prefixWithError", "GET", "/health/live?fail=1", true},
+		{"glob", "GET", "/static/js/app.js", false},
+		{"globNotFound", "GET", "/static/js/app.js?missing=1", true},
+		{"methodRegex", "HEAD", "/api/v1/ping", false},
+		{"otherMethod", "GET", "/api/v1/ping", true},
+		{"route", "GET", "/users/12", false},
+		{"notSkipped", "GET", "/logged", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook.Reset()
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.url, nil))
+			if tt.isWant {
+				req.NotNil(t, hook.LastEntry())
+			} else {
+				req.Nil(t, hook.LastEntry())
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 21:48:15
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 21:48:15
+ * @FilePath: /xlogrus/gin_slow.go
+ * @Description: slow request threshold for gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	c "github.com/justin-ren/xlogrus/common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type SlowRule struct {
+	RouteMatcher
+	//threshold for matched routes, 0 to disable such as long polling
+	Threshold time.Duration
+}
+
+// SetSlowThreshold sets threshold of slow request, 0 to disable.
+func (opt *GinOpt) SetSlowThreshold(threshold time.Duration) error {
+	if threshold < 0 {
+		return errors.New("slow threshold cannot be negative")
+	}
+	opt.SlowThreshold = threshold
+	return nil
+}
+
+// SetSlowRules sets threshold per route, the first matched rule is used.
+func (opt *GinOpt) SetSlowRules(rules []SlowRule) error {
+	//compile a copy, rules of caller may be shared
+	rules = append([]SlowRule(nil), rules...)
+	for i := range rules {
+		if rules[i].Threshold < 0 {
+			return errors.New("slow threshold cannot be negative")
+		}
+		if err := rules[i].compile(); err != nil {
+			return err
+		}
+	}
+	opt.SlowRules = rules
+	return nil
+}
+
+// WithSlowRules 设置按路由覆盖的慢请求阈值
+func WithSlowRules[
+	T any,
+	PT interface {
+		*T
+		SetSlowRules([]SlowRule) error
+	},
+](rules []SlowRule) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetSlowRules(rules)
+	})
+}
+
+// slowThreshold returns threshold for request, 0 if disabled
+func (opt *GinOpt) slowThreshold(ctx *gin.Context) time.Duration {
+	for i := range opt.SlowRules {
+		if opt.SlowRules[i].Match(ctx) {
+			return opt.SlowRules[i].Threshold
+		}
+	}
+	return opt.SlowThreshold
+}
+
+// slowFields returns slow and slowReason fields, nil if it's not slow
+func (opt *GinOpt) slowFields(ctx *gin.Context, latency time.Duration) logrus.Fields {
+	threshold := opt.slowThreshold(ctx)
+	if threshold == 0 || latency < threshold {
+		return nil
+	}
+	return logrus.Fields{
+		"slow":       true,
+		"slowReason": fmt.Sprintf("latency %v exceeds %v", latency, threshold),
+	}
+}
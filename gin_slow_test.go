@@ -0,0 +1,75 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 22:06:43
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 22:06:43
+ * @FilePath: /xlogrus/gin_slow_test.go
+ * @Description: test slow request threshold of gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	req "github.com/stretchr/testify/require" //exit if failed
+)
+
+func TestGinSlowThreshold(t *testing.T) {
+	_, _, _, err := NewGinLog(WithSlowThreshold[GinOpt](-time.Second))
+	req.Error(t, err)
+	_, _, _, err = NewGinLog(WithSlowRules[GinOpt]([]SlowRule{{RouteMatcher: RouteMatcher{Glob: "/a"}, Threshold: -1}}))
+	req.Error(t, err)
+
+	r, hook, _ := newTestGin(t,
+		WithSlowThreshold[GinOpt](20*time.Millisecond),
+		WithSlowRules[GinOpt]([]SlowRule{
+			{RouteMatcher: RouteMatcher{Glob: "/poll/**"}, Threshold: 0},
+			{RouteMatcher: RouteMatcher{Route: "/report/:id"}, Threshold: time.Hour},
+		}),
+	)
+	sleep := func(ctx *gin.Context) {
+		time.Sleep(30 * time.Millisecond)
+		status := http.StatusOK
+		if ctx.Query("fail") != "" {
+			status = http.StatusInternalServerError
+		}
+		ctx.Status(status)
+	}
+	r.GET("/fast", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	r.GET("/slow", sleep)
+	r.GET("/poll/events", sleep)
+	r.GET("/report/:id", sleep)
+
+	tests := []struct {
+		path      string
+		wantSlow  bool
+		wantLevel logrus.Level
+	}{
+		{"/fast", false, logrus.InfoLevel},
+		{"/slow", true, logrus.WarnLevel},
+		//error level is kept
+		{"/slow?fail=1", true, logrus.ErrorLevel},
+		{"/poll/events", false, logrus.InfoLevel},
+		{"/report/1", false, logrus.InfoLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+			entry := hook.LastEntry()
+			req.Equal(t, tt.wantLevel, entry.Level)
+			if !tt.wantSlow {
+				req.NotContains(t, entry.Data, "slow")
+				return
+			}
+			req.Equal(t, true, entry.Data["slow"])
+			req.Contains(t, entry.Data["slowReason"], "exceeds 20ms")
+		})
+	}
+}
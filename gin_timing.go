@@ -0,0 +1,51 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 13:21:47
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 13:21:47
+ * @FilePath: /xlogrus/gin_timing.go
+ * @Description: timing breakdown of request for gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// track records first byte and time spent in writing to client
+func (w *accessWriter) track(begin time.Time) {
+	if w.firstByte.IsZero() && w.ResponseWriter.Written() {
+		w.firstByte = begin
+	}
+	w.writing += time.Since(begin)
+}
+
+/*timingFields
+ * @msg timing breakdown in milliseconds, it's numeric for percentiles:
+ *		latencyMs total time, ttfbMs time to first byte, omitted if nothing is
+ *		written by handlers, writeMs time spent in writing to client,
+ *		handlerMs the rest of latencyMs
+ * @receiver w
+ * @param latency
+ * @return: logrus.Fields
+ */
+func (w *accessWriter) timingFields(latency time.Duration) logrus.Fields {
+	fields := logrus.Fields{
+		"latencyMs": milliseconds(latency),
+		"handlerMs": milliseconds(latency - w.writing),
+		"writeMs":   milliseconds(w.writing),
+	}
+	if !w.firstByte.IsZero() {
+		fields["ttfbMs"] = milliseconds(w.firstByte.Sub(w.start))
+	}
+	return fields
+}
+
+// milliseconds returns d in milliseconds with microsecond precision
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,53 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 13:40:22
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 13:40:22
+ * @FilePath: /xlogrus/gin_timing_test.go
+ * @Description: test timing breakdown of gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	req "github.com/stretchr/testify/require" //exit if failed
+)
+
+func TestGinTiming(t *testing.T) {
+	req.Equal(t, 7.5, milliseconds(7500*time.Microsecond))
+	req.Equal(t, 0.007, milliseconds(7500*time.Nanosecond))
+
+	r, hook, _ := newTestGin(t)
+	r.GET("/report", func(ctx *gin.Context) {
+		time.Sleep(20 * time.Millisecond)
+		ctx.String(http.StatusOK, "head")
+		time.Sleep(10 * time.Millisecond)
+		_, _ = ctx.Writer.WriteString("tail")
+	})
+	r.GET("/empty", func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/report", nil))
+	data := hook.LastEntry().Data
+	latency := data["latencyMs"].(float64)
+	ttfb := data["ttfbMs"].(float64)
+	req.Equal(t, milliseconds(data["latency"].(time.Duration)), latency)
+	req.GreaterOrEqual(t, latency, 30.0)
+	req.GreaterOrEqual(t, ttfb, 20.0)
+	req.Less(t, ttfb, latency)
+	req.InDelta(t, latency, data["handlerMs"].(float64)+data["writeMs"].(float64), 0.002)
+
+	//header is written by gin after handlers
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/empty", nil))
+	data = hook.LastEntry().Data
+	req.NotContains(t, data, "ttfbMs")
+	req.Equal(t, 0.0, data["writeMs"])
+}
@@ -0,0 +1,60 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 19:20:36
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 19:20:36
+ * @FilePath: /xlogrus/gin_trace.go
+ * @Description: W3C traceparent propagation for gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"github.com/gin-gonic/gin"
+	c "github.com/justin-ren/xlogrus/common"
+	"github.com/sirupsen/logrus"
+)
+
+// SetTracePropagation sets whether to parse traceparent and start server span.
+func (opt *GinOpt) SetTracePropagation(enabled bool) error {
+	opt.TracePropagation = enabled
+	return nil
+}
+
+// WithTracePropagation 设置是否解析traceparent并记录traceId/spanId
+func WithTracePropagation[
+	T any,
+	PT interface {
+		*T
+		SetTracePropagation(bool) error
+	},
+](enabled bool) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetTracePropagation(enabled)
+	})
+}
+
+/*startSpan
+ * @msg start server span from traceparent header and put it into request
+ *		context, use InjectTraceparent(ctx, header) for outgoing calls
+ * @receiver opt
+ * @param ctx
+ * @return: logrus.Fields traceId, spanId and parentSpanId, nil if disabled
+ */
+func (opt *GinOpt) startSpan(ctx *gin.Context) logrus.Fields {
+	if !opt.TracePropagation {
+		return nil
+	}
+	sc := StartSpan(ctx.Request.Header)
+	ctx.Request = ctx.Request.WithContext(ContextWithSpan(ctx.Request.Context(), sc))
+	fields := logrus.Fields{
+		"traceId": sc.TraceID,
+		"spanId":  sc.SpanID,
+	}
+	if sc.ParentSpanID != "" {
+		fields["parentSpanId"] = sc.ParentSpanID
+	}
+	AddFields(ctx, fields)
+	return fields
+}
@@ -0,0 +1,58 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 19:42:10
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 19:42:10
+ * @FilePath: /xlogrus/gin_trace_test.go
+ * @Description: test traceparent propagation in gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	req "github.com/stretchr/testify/require" //exit if failed
+)
+
+func TestGinTracePropagation(t *testing.T) {
+	r, hook, _ := newTestGin(t, WithTracePropagation[GinOpt](true))
+	var outgoing http.Header
+	r.GET("/call", func(ctx *gin.Context) {
+		outgoing = http.Header{}
+		InjectTraceparent(ctx, outgoing)
+		ctx.Status(http.StatusOK)
+	})
+
+	t.Run("fromCaller", func(t *testing.T) {
+		request := httptest.NewRequest("GET", "/call", nil)
+		request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+		request.Header.Set("tracestate", "congo=t61rcWkgMzE")
+		r.ServeHTTP(httptest.NewRecorder(), request)
+
+		data := hook.LastEntry().Data
+		req.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", data["traceId"])
+		req.Equal(t, "00f067aa0ba902b7", data["parentSpanId"])
+		spanID := data["spanId"].(string)
+		req.Len(t, spanID, 16)
+		req.NotEqual(t, "00f067aa0ba902b7", spanID)
+		//server span is parent of outgoing call
+		req.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-"+spanID+"-00", outgoing.Get("traceparent"))
+		req.Equal(t, "congo=t61rcWkgMzE", outgoing.Get("tracestate"))
+	})
+
+	t.Run("newTrace", func(t *testing.T) {
+		request := httptest.NewRequest("GET", "/call", nil)
+		request.Header.Set("traceparent", "00-00000000000000000000000000000000-00f067aa0ba902b7-01")
+		r.ServeHTTP(httptest.NewRecorder(), request)
+
+		data := hook.LastEntry().Data
+		req.Len(t, data["traceId"], 32)
+		req.NotContains(t, data, "parentSpanId")
+		req.Regexp(t, `^00-[0-9a-f]{32}-[0-9a-f]{16}-01$`, outgoing.Get("traceparent"))
+	})
+}
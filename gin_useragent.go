@@ -0,0 +1,159 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 11:52:09
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 11:52:09
+ * @FilePath: /xlogrus/gin_useragent.go
+ * @Description: user agent fields and bot policy for gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"github.com/gin-gonic/gin"
+	c "github.com/justin-ren/xlogrus/common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// policy for requests from bots and health checkers
+const (
+	BotLog       = "log"       //same as other requests
+	BotDowngrade = "downgrade" //debug level unless it's error level
+	BotSkip      = "skip"      //not logged unless ctx.Errors is not empty
+)
+
+// SetLogUserAgent sets whether to log ua.* fields parsed from User-Agent.
+func (opt *GinOpt) SetLogUserAgent(enabled bool) error {
+	opt.LogUserAgent = enabled
+	return nil
+}
+
+// SetUserAgentCache sets LRU cache size of parsed user agents, 0 to parse without cache.
+func (opt *GinOpt) SetUserAgentCache(size int) error {
+	if size < 0 {
+		return errors.New("user agent cache size cannot be negative")
+	}
+	opt.UserAgentCache = size
+	return nil
+}
+
+// SetBotPolicy sets policy for bots, log, downgrade or skip.
+func (opt *GinOpt) SetBotPolicy(policy string) error {
+	switch policy {
+	case BotLog, BotDowngrade, BotSkip:
+		opt.BotPolicy = policy
+		return nil
+	}
+	return errors.Errorf("invalid bot policy %q", policy)
+}
+
+// SetBotPatterns sets extra user agent tokens of bots such as internal health checkers.
+func (opt *GinOpt) SetBotPatterns(patterns []string) error {
+	opt.BotPatterns = patterns
+	return nil
+}
+
+// WithLogUserAgent 设置是否记录解析User-Agent得到的ua.*字段
+func WithLogUserAgent[
+	T any,
+	PT interface {
+		*T
+		SetLogUserAgent(bool) error
+	},
+](enabled bool) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetLogUserAgent(enabled)
+	})
+}
+
+// WithUserAgentCache 设置解析User-Agent的LRU缓存大小, 0为不缓存
+func WithUserAgentCache[
+	T any,
+	PT interface {
+		*T
+		SetUserAgentCache(int) error
+	},
+](size int) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetUserAgentCache(size)
+	})
+}
+
+// WithBotPolicy 设置爬虫和健康检查请求的记录方式: log, downgrade, skip
+func WithBotPolicy[
+	T any,
+	PT interface {
+		*T
+		SetBotPolicy(string) error
+	},
+](policy string) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetBotPolicy(policy)
+	})
+}
+
+// WithBotPatterns 设置额外的爬虫User-Agent关键字, 不区分大小写
+func WithBotPatterns[
+	T any,
+	PT interface {
+		*T
+		SetBotPatterns([]string) error
+	},
+](patterns []string) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetBotPatterns(patterns)
+	})
+}
+
+// initUserAgent creates parser after options are applied, user agent is parsed
+// if ua.* fields are logged or bots are not logged as others
+func (opt *GinOpt) initUserAgent() {
+	if opt.LogUserAgent || opt.BotPolicy != BotLog {
+		opt.uaParser = NewUserAgentParser(opt.UserAgentCache, opt.BotPatterns...)
+	}
+}
+
+// userAgent returns parsed user agent of request, nil if disabled
+func (opt *GinOpt) userAgent(ctx *gin.Context) *UserAgent {
+	if opt.uaParser == nil {
+		return nil
+	}
+	ua := opt.uaParser.Parse(ctx.Request.UserAgent())
+	return &ua
+}
+
+// botSkipped reports whether request from bot is skipped, it's never skipped with errors
+func (opt *GinOpt) botSkipped(ctx *gin.Context, ua *UserAgent) bool {
+	return ua != nil && ua.IsBot && opt.BotPolicy == BotSkip && len(ctx.Errors) == 0
+}
+
+// botLevel lowers level to debug for bots if it's downgraded, error level is kept
+// and debug or trace level is never raised
+func (opt *GinOpt) botLevel(ua *UserAgent, level logrus.Level) logrus.Level {
+	if ua != nil && ua.IsBot && opt.BotPolicy == BotDowngrade &&
+		level > logrus.ErrorLevel && level < logrus.DebugLevel {
+		return logrus.DebugLevel
+	}
+	return level
+}
+
+// userAgentFields returns ua.* fields, empty values are omitted
+func (opt *GinOpt) userAgentFields(ua *UserAgent) logrus.Fields {
+	if ua == nil || !opt.LogUserAgent {
+		return nil
+	}
+	fields := logrus.Fields{"ua.isBot": ua.IsBot}
+	for k, v := range map[string]string{
+		"ua.browser": ua.Browser,
+		"ua.version": ua.Version,
+		"ua.os":      ua.OS,
+		"ua.device":  ua.Device,
+	} {
+		if v != "" {
+			fields[k] = v
+		}
+	}
+	return fields
+}
@@ -0,0 +1,93 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 23:36:12
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 23:36:12
+ * @FilePath: /xlogrus/gin_useragent_test.go
+ * @Description: test user agent fields and bot policy in gin access log
+ *
+ */
+
+package xlogrus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	req "github.com/stretchr/testify/require" //exit if failed
+)
+
+func TestGinUserAgent(t *testing.T) {
+	_, _, _, err := NewGinLog(WithBotPolicy[GinOpt]("ignore"))
+	req.Error(t, err)
+	_, _, _, err = NewGinLog(WithUserAgentCache[GinOpt](-1))
+	req.Error(t, err)
+
+	googlebot := "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+	tests := []struct {
+		policy    string
+		cache     int
+		ua        string
+		path      string
+		wantLog   bool
+		wantLevel logrus.Level
+	}{
+		{BotLog, 16, googlebot, "/ok", true, logrus.InfoLevel},
+		{BotLog, 0, googlebot, "/ok", true, logrus.InfoLevel},
+		{BotDowngrade, 16, googlebot, "/missing", true, logrus.DebugLevel},
+		{BotDowngrade, 16, googlebot, "/fail", true, logrus.ErrorLevel},
+		{BotDowngrade, 16, googlebot, "/trace", true, logrus.TraceLevel},
+		{BotSkip, 0, "kube-probe/1.28", "/ok", false, 0},
+		{BotSkip, 16, "Checker/1.0", "/ok", false, 0},
+		{BotSkip, 16, "curl/8.4.0", "/ok", true, logrus.InfoLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy+tt.ua, func(t *testing.T) {
+			r, hook, _ := newTestGin(t,
+				WithLogUserAgent[GinOpt](true),
+				WithUserAgentCache[GinOpt](tt.cache),
+				WithBotPolicy[GinOpt](tt.policy),
+				WithBotPatterns[GinOpt]([]string{"checker"}),
+				WithLogLevel[GinOpt]("trace"),
+				WithStatusLevels[GinOpt]([]StatusLevel{
+					{StatusRange: StatusCode(http.StatusOK), RouteMatcher: RouteMatcher{Route: "/trace"}, Level: logrus.TraceLevel},
+				}),
+			)
+			r.GET("/ok", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+			r.GET("/fail", func(ctx *gin.Context) { ctx.Status(http.StatusInternalServerError) })
+			r.GET("/trace", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+			request := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			request.Header.Set("User-Agent", tt.ua)
+			r.ServeHTTP(httptest.NewRecorder(), request)
+			if !tt.wantLog {
+				req.Empty(t, hook.AllEntries())
+				return
+			}
+			entry := hook.LastEntry()
+			req.Equal(t, tt.wantLevel, entry.Level)
+			ua := ParseUserAgent(tt.ua)
+			req.Equal(t, ua.Browser, entry.Data["ua.browser"])
+			req.Equal(t, ua.IsBot, entry.Data["ua.isBot"])
+			req.Equal(t, ua.Device, entry.Data["ua.device"])
+		})
+	}
+
+	//bot policy works without ua.* fields
+	r, hook, opt := newTestGin(t, WithBotPolicy[GinOpt](BotDowngrade))
+	r.GET("/ok", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	request := httptest.NewRequest(http.MethodGet, "/ok", nil)
+	request.Header.Set("User-Agent", googlebot)
+	r.ServeHTTP(httptest.NewRecorder(), request)
+	req.Equal(t, logrus.DebugLevel, hook.LastEntry().Level)
+	req.Empty(t, subFields(hook.LastEntry().Data, "ua."))
+
+	//user agent is not parsed by default
+	_, _, opt, err = NewGinLog(WithLogPath[GinOpt](t.TempDir() + "/"))
+	req.NoError(t, err)
+	defer opt.Close()
+	req.Nil(t, opt.uaParser)
+}
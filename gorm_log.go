@@ -12,7 +12,6 @@ package xlogrus
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	c "github.com/justin-ren/xlogrus/common"
@@ -34,12 +33,17 @@ func (gormLog *GormLog) LogMode(level logger.LogLevel) logger.Interface {
 	return gormLog
 }
 
+// entry creates log entry with fields carried by ctx, see WithLogger
+func (gormLog *GormLog) entry(ctx context.Context) *logrus.Entry {
+	return gormLog.Logger.WithContext(ctx).WithFields(fieldsFromContext(ctx))
+}
+
 func (gormLog *GormLog) Info(ctx context.Context, msg string, args ...interface{}) {
-	gormLog.Logger.WithContext(ctx).WithFields(logrus.Fields{"msg": gormLog.ignoreBKeyword(msg)}).Info(args...)
+	gormLog.entry(ctx).WithFields(logrus.Fields{"msg": gormLog.ignoreBKeyword(msg)}).Info(args...)
 }
 
 func (gormLog *GormLog) Warn(ctx context.Context, msg string, args ...interface{}) {
-	gormLog.Logger.WithContext(ctx).WithFields(
+	gormLog.entry(ctx).WithFields(
 		logrus.Fields{
 			"from": utils.FileWithLineNum(),
 			"msg":  gormLog.ignoreBKeyword(msg),
@@ -47,7 +51,7 @@ func (gormLog *GormLog) Warn(ctx context.Context, msg string, args ...interface{
 }
 
 func (gormLog *GormLog) Error(ctx context.Context, msg string, args ...interface{}) {
-	gormLog.Logger.WithContext(ctx).WithFields(
+	gormLog.entry(ctx).WithFields(
 		logrus.Fields{
 			"from": utils.FileWithLineNum(),
 			"msg":  gormLog.ignoreBKeyword(msg),
@@ -75,15 +79,15 @@ func (gormLog *GormLog) Trace(ctx context.Context, begin time.Time, fc func() (s
 		(!errors.Is(err, gorm.ErrRecordNotFound) || gormLog.Opt.SkipErrRecordNotFound):
 
 		fields["err"] = errors.Cause(err)
-		gormLog.Logger.WithFields(fields).Error()
+		gormLog.entry(ctx).WithFields(fields).Error()
 
 	case elapsed > gormLog.Opt.SlowThreshold && gormLog.Opt.SlowThreshold != 0 && gormLog.Opt.GormLogLevel >= logger.Warn:
 		slowLog := fmt.Sprintf("SLOW SQL >= %v", gormLog.Opt.SlowThreshold)
 		fields["reason"] = slowLog
-		gormLog.Logger.WithContext(ctx).WithFields(fields).Warn()
+		gormLog.entry(ctx).WithFields(fields).Warn()
 
 	case gormLog.Opt.GormLogLevel == logger.Info:
-		gormLog.Logger.WithContext(ctx).WithFields(fields).Info()
+		gormLog.entry(ctx).WithFields(fields).Info()
 	}
 }
 
@@ -102,23 +106,15 @@ type BannedKeyword struct {
 func GetGormOpt() *GormOpt {
 	opt := c.InitOpt()
 	opt.FileNamePrefix = "db.log"
+	opt.LoggerName = "gorm"
 	return &GormOpt{
 		SkipErrRecordNotFound: true,
 		SlowThreshold:         500 * time.Millisecond,
 		IsHelper:              true,
-		BKeywords: []BannedKeyword{
-			{
-				"password",
-				false,
-			},
-			{
-				"pwd",
-				false,
-			},
-		},
-		LogLatency:   true,
-		GormLogLevel: logger.Warn,
-		OptLog:       opt,
+		BKeywords:             defaultBKeywords(),
+		LogLatency:            true,
+		GormLogLevel:          logger.Warn,
+		OptLog:                opt,
 	}
 }
 
@@ -129,28 +125,7 @@ func GetGormOpt() *GormOpt {
  * @return: string
  */
 func (gormLog *GormLog) ignoreBKeyword(lContent string) string {
-	if len(gormLog.Opt.BKeywords) <= 0 {
-		return lContent
-	}
-	arrLine := strings.Split(strings.Trim(lContent, "\n"), "\n")
-	for idx := 0; idx < len(gormLog.Opt.BKeywords); idx++ {
-		for i := 0; i < len(arrLine); i++ {
-			if gormLog.Opt.BKeywords[idx].IsCaseSensitive &&
-				strings.Contains(arrLine[i], gormLog.Opt.BKeywords[idx].Keyword) {
-				//found with case-sensitive
-				arrLine[i] = fmt.Sprintf("ignored line with banned word %v",
-					gormLog.Opt.BKeywords[idx].Keyword)
-			} else if !gormLog.Opt.BKeywords[idx].IsCaseSensitive &&
-				strings.Contains(
-					strings.ToLower(arrLine[i]),
-					strings.ToLower(gormLog.Opt.BKeywords[idx].Keyword),
-				) { //found with ignore case-sensitive
-				arrLine[i] = fmt.Sprintf("ignored line with banned word: %v",
-					gormLog.Opt.BKeywords[idx].Keyword)
-			}
-		}
-	}
-	return strings.Join(arrLine, "\n")
+	return ignoreBKeyword(gormLog.Opt.BKeywords, lContent)
 }
 
 type GormOpt struct {
@@ -0,0 +1,49 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 10:31:18
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 10:31:18
+ * @FilePath: /xlogrus/metrics_test.go
+ * @Description: test prometheus metrics for log volume
+ *
+ */
+
+package xlogrus
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	req "github.com/stretchr/testify/require" //exit if failed
+)
+
+func TestMetrics(t *testing.T) {
+	m := NewMetrics()
+	path := t.TempDir() + "/"
+	lg, opt, err := NewUserLog(
+		WithLogPath[UserOpt](path),
+		WithMetrics[UserOpt](m),
+	)
+	req.NoError(t, err)
+	defer opt.Close()
+	lg.SetOutput(io.Discard)
+
+	lg.Info("info msg")
+	lg.Error("error msg")
+	lg.Error("error msg")
+
+	req.Equal(t, uint64(1), m.Entries("user", logrus.InfoLevel))
+	req.Equal(t, uint64(2), m.Entries("user", logrus.ErrorLevel))
+	req.Greater(t, m.Bytes(path+"trace.log"), uint64(0))
+	req.Greater(t, m.Bytes(path+"error.log"), uint64(0))
+
+	response := httptest.NewRecorder()
+	m.ServeHTTP(response, httptest.NewRequest("GET", "/metrics", nil))
+	body := response.Body.String()
+	req.Contains(t, response.Header().Get("Content-Type"), "text/plain; version=0.0.4")
+	req.Contains(t, body, "# TYPE xlogrus_log_entries_total counter")
+	req.Contains(t, body, `xlogrus_log_entries_total{logger="user",level="error"} 2`)
+	req.Contains(t, body, `xlogrus_log_bytes_total{file="`+path+`trace.log"}`)
+}
@@ -0,0 +1,689 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 11:10:05
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 11:10:05
+ * @FilePath: /xlogrus/otlp.go
+ * @Description: export logrus entries as OTLP/HTTP JSON logs
+ *
+ */
+
+package xlogrus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	c "github.com/justin-ren/xlogrus/common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type OTLPOpt struct {
+	//OTLP/HTTP logs endpoint of collector
+	Endpoint string
+	//resource attribute service.name
+	ServiceName string
+	//other resource attributes, such as deployment.environment
+	ResourceAttrs map[string]string
+	//extra http headers, such as authorization
+	Headers map[string]string
+	//max records in one request
+	BatchSize int
+	//max records waiting for export, new records are dropped if full
+	QueueSize int
+	//flush batch even it's not full
+	FlushInterval time.Duration
+	//retry count for failed request
+	MaxRetries int
+	//initial backoff for retry, doubled for every retry
+	RetryBackoff time.Duration
+	//timeout for every request
+	Timeout time.Duration
+}
+
+func GetOTLPOpt() *OTLPOpt {
+	return &OTLPOpt{
+		Endpoint:      "http://localhost:4318/v1/logs",
+		ServiceName:   "unknown_service",
+		BatchSize:     512,
+		QueueSize:     4096,
+		FlushInterval: 5 * time.Second,
+		MaxRetries:    5,
+		RetryBackoff:  500 * time.Millisecond,
+		Timeout:       10 * time.Second,
+	}
+}
+
+// SetEndpoint sets the OTLP/HTTP logs endpoint.
+func (o *OTLPOpt) SetEndpoint(endpoint string) error {
+	if endpoint == "" {
+		return errors.New("endpoint cannot be empty")
+	}
+	o.Endpoint = endpoint
+	return nil
+}
+
+// SetServiceName sets resource attribute service.name.
+func (o *OTLPOpt) SetServiceName(name string) error {
+	o.ServiceName = name
+	return nil
+}
+
+// SetResourceAttrs sets other resource attributes.
+func (o *OTLPOpt) SetResourceAttrs(attrs map[string]string) error {
+	o.ResourceAttrs = attrs
+	return nil
+}
+
+// SetHeaders sets extra http headers.
+func (o *OTLPOpt) SetHeaders(headers map[string]string) error {
+	o.Headers = headers
+	return nil
+}
+
+// SetBatchSize sets max records in one request.
+func (o *OTLPOpt) SetBatchSize(size int) error {
+	if size <= 0 {
+		return errors.New("batch size must be positive")
+	}
+	o.BatchSize = size
+	return nil
+}
+
+// SetQueueSize sets max records waiting for export.
+func (o *OTLPOpt) SetQueueSize(size int) error {
+	if size <= 0 {
+		return errors.New("queue size must be positive")
+	}
+	o.QueueSize = size
+	return nil
+}
+
+// SetFlushInterval sets interval to flush batch.
+func (o *OTLPOpt) SetFlushInterval(interval time.Duration) error {
+	if interval <= 0 {
+		return errors.New("flush interval must be positive")
+	}
+	o.FlushInterval = interval
+	return nil
+}
+
+// SetMaxRetries sets retry count for failed request.
+func (o *OTLPOpt) SetMaxRetries(count int) error {
+	if count < 0 {
+		return errors.New("max retries cannot be negative")
+	}
+	o.MaxRetries = count
+	return nil
+}
+
+// SetRetryBackoff sets initial backoff for retry.
+func (o *OTLPOpt) SetRetryBackoff(backoff time.Duration) error {
+	if backoff < 0 {
+		return errors.New("retry backoff cannot be negative")
+	}
+	o.RetryBackoff = backoff
+	return nil
+}
+
+// SetTimeout sets timeout for every request.
+func (o *OTLPOpt) SetTimeout(timeout time.Duration) error {
+	if timeout < 0 {
+		return errors.New("timeout cannot be negative")
+	}
+	o.Timeout = timeout
+	return nil
+}
+
+// WithOTLPEndpoint 设置OTLP/HTTP日志接收地址
+func WithOTLPEndpoint[
+	T any,
+	PT interface {
+		*T
+		SetEndpoint(string) error
+	},
+](endpoint string) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetEndpoint(endpoint)
+	})
+}
+
+// WithOTLPServiceName 设置service.name
+func WithOTLPServiceName[
+	T any,
+	PT interface {
+		*T
+		SetServiceName(string) error
+	},
+](name string) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetServiceName(name)
+	})
+}
+
+// WithOTLPResourceAttrs 设置其他resource属性
+func WithOTLPResourceAttrs[
+	T any,
+	PT interface {
+		*T
+		SetResourceAttrs(map[string]string) error
+	},
+](attrs map[string]string) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetResourceAttrs(attrs)
+	})
+}
+
+// WithOTLPHeaders 设置请求头
+func WithOTLPHeaders[
+	T any,
+	PT interface {
+		*T
+		SetHeaders(map[string]string) error
+	},
+](headers map[string]string) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetHeaders(headers)
+	})
+}
+
+// WithOTLPBatchSize 设置每次请求的最大记录数
+func WithOTLPBatchSize[
+	T any,
+	PT interface {
+		*T
+		SetBatchSize(int) error
+	},
+](size int) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetBatchSize(size)
+	})
+}
+
+// WithOTLPQueueSize 设置等待发送的最大记录数
+func WithOTLPQueueSize[
+	T any,
+	PT interface {
+		*T
+		SetQueueSize(int) error
+	},
+](size int) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetQueueSize(size)
+	})
+}
+
+// WithOTLPFlushInterval 设置发送间隔
+func WithOTLPFlushInterval[
+	T any,
+	PT interface {
+		*T
+		SetFlushInterval(time.Duration) error
+	},
+](interval time.Duration) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetFlushInterval(interval)
+	})
+}
+
+// WithOTLPMaxRetries 设置失败重试次数
+func WithOTLPMaxRetries[
+	T any,
+	PT interface {
+		*T
+		SetMaxRetries(int) error
+	},
+](count int) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetMaxRetries(count)
+	})
+}
+
+// WithOTLPRetryBackoff 设置重试的初始间隔
+func WithOTLPRetryBackoff[
+	T any,
+	PT interface {
+		*T
+		SetRetryBackoff(time.Duration) error
+	},
+](backoff time.Duration) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetRetryBackoff(backoff)
+	})
+}
+
+// WithOTLPTimeout 设置请求超时
+func WithOTLPTimeout[
+	T any,
+	PT interface {
+		*T
+		SetTimeout(time.Duration) error
+	},
+](timeout time.Duration) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetTimeout(timeout)
+	})
+}
+
+/*OTLPExporter
+ * @msg logrus hook to convert entries to OTLP log records, which are batched
+ *		and posted to collector as OTLP/HTTP JSON by background goroutine
+ */
+type OTLPExporter struct {
+	Opt *OTLPOpt
+
+	client   *http.Client
+	ctx      context.Context
+	cancel   context.CancelFunc
+	resource otlpResource
+	records  chan otlpLogRecord
+	flush    chan chan struct{}
+	done     chan struct{}
+	closed   atomic.Bool
+	once     sync.Once
+	wg       sync.WaitGroup
+
+	dropped atomic.Uint64
+	failed  atomic.Uint64
+	mu      sync.Mutex
+	lastErr error
+}
+
+func NewOTLPExporter(setFunc ...c.LogOption[OTLPOpt]) (*OTLPExporter, *OTLPOpt, error) {
+	opt := GetOTLPOpt()
+	for _, f := range setFunc {
+		if err := f.Apply(opt); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to apply otlp option")
+		}
+	}
+
+	exp := &OTLPExporter{
+		Opt:     opt,
+		client:  &http.Client{Timeout: opt.Timeout},
+		records: make(chan otlpLogRecord, opt.QueueSize),
+		flush:   make(chan chan struct{}),
+		done:    make(chan struct{}),
+	}
+	exp.ctx, exp.cancel = context.WithCancel(context.Background())
+	exp.resource.Attributes = append(exp.resource.Attributes, otlpKeyValue{
+		"service.name", otlpValue{StringValue: &opt.ServiceName},
+	})
+	keys := make([]string, 0, len(opt.ResourceAttrs))
+	for k := range opt.ResourceAttrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		exp.resource.Attributes = append(exp.resource.Attributes, otlpKeyValue{k, toOTLPValue(opt.ResourceAttrs[k])})
+	}
+
+	exp.wg.Add(1)
+	go exp.run()
+	return exp, opt, nil
+}
+
+// Levels implements logrus.Hook
+func (exp *OTLPExporter) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook, record is dropped if queue is full
+func (exp *OTLPExporter) Fire(entry *logrus.Entry) error {
+	if exp.closed.Load() {
+		return nil
+	}
+	select {
+	case exp.records <- toOTLPRecord(entry):
+	default:
+		exp.dropped.Add(1)
+	}
+	return nil
+}
+
+// Flush sends all queued records and waits until it's done.
+func (exp *OTLPExporter) Flush() {
+	if exp.closed.Load() {
+		return
+	}
+	ch := make(chan struct{})
+	select {
+	case exp.flush <- ch:
+		<-ch
+	case <-exp.done:
+	}
+}
+
+// Close flushes queued records without retry and stops background goroutine,
+// requests still running after Timeout are cancelled.
+func (exp *OTLPExporter) Close() error {
+	exp.once.Do(func() {
+		exp.closed.Store(true)
+		close(exp.done)
+		finished := make(chan struct{})
+		go func() {
+			exp.wg.Wait()
+			close(finished)
+		}()
+		if exp.Opt.Timeout > 0 {
+			select {
+			case <-finished:
+			case <-time.After(exp.Opt.Timeout):
+				exp.cancel()
+			}
+		}
+		<-finished
+		exp.cancel()
+	})
+	return exp.LastError()
+}
+
+// Dropped returns count of records dropped for full queue.
+func (exp *OTLPExporter) Dropped() uint64 {
+	return exp.dropped.Load()
+}
+
+// Failed returns count of records failed to export after all retries.
+func (exp *OTLPExporter) Failed() uint64 {
+	return exp.failed.Load()
+}
+
+// LastError returns the latest export error.
+func (exp *OTLPExporter) LastError() error {
+	exp.mu.Lock()
+	defer exp.mu.Unlock()
+	return exp.lastErr
+}
+
+func (exp *OTLPExporter) run() {
+	defer exp.wg.Done()
+	ticker := time.NewTicker(exp.Opt.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]otlpLogRecord, 0, exp.Opt.BatchSize)
+	send := func() {
+		if len(batch) > 0 {
+			exp.export(batch)
+			batch = make([]otlpLogRecord, 0, exp.Opt.BatchSize)
+		}
+	}
+	//drain queued records without blocking
+	drain := func() {
+		for {
+			select {
+			case r := <-exp.records:
+				if batch = append(batch, r); len(batch) >= exp.Opt.BatchSize {
+					send()
+				}
+			default:
+				send()
+				return
+			}
+		}
+	}
+	for {
+		select {
+		case r := <-exp.records:
+			if batch = append(batch, r); len(batch) >= exp.Opt.BatchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case ch := <-exp.flush:
+			drain()
+			close(ch)
+		case <-exp.done:
+			drain()
+			return
+		}
+	}
+}
+
+// export posts batch with retry and exponential backoff
+func (exp *OTLPExporter) export(batch []otlpLogRecord) {
+	body, err := json.Marshal(otlpRequest{
+		ResourceLogs: []otlpResourceLogs{{
+			Resource: exp.resource,
+			ScopeLogs: []otlpScopeLogs{{
+				Scope:      otlpScope{Name: "github.com/justin-ren/xlogrus"},
+				LogRecords: batch,
+			}},
+		}},
+	})
+	if err != nil {
+		exp.setErr(errors.Wrap(err, "failed to marshal otlp logs"), len(batch))
+		return
+	}
+
+	backoff := exp.Opt.RetryBackoff
+	for i := 0; ; i++ {
+		retry, err := exp.post(body)
+		if err == nil {
+			return
+		}
+		if !retry || i >= exp.Opt.MaxRetries {
+			exp.setErr(err, len(batch))
+			return
+		}
+		//stop retrying once it's closed
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-exp.done:
+			timer.Stop()
+			exp.setErr(errors.Wrap(err, "exporter closed"), len(batch))
+			return
+		}
+		backoff *= 2
+	}
+}
+
+// post sends body once, retry is true if it's worth to try again
+func (exp *OTLPExporter) post(body []byte) (retry bool, err error) {
+	request, err := http.NewRequestWithContext(exp.ctx, http.MethodPost, exp.Opt.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create otlp request")
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for k, v := range exp.Opt.Headers {
+		request.Header.Set(k, v)
+	}
+	response, err := exp.client.Do(request)
+	if err != nil {
+		return true, errors.Wrap(err, "failed to post otlp logs")
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return false, nil
+	}
+	err = errors.Errorf("failed to post otlp logs: %s", response.Status)
+	switch response.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true, err
+	}
+	return false, err
+}
+
+func (exp *OTLPExporter) setErr(err error, count int) {
+	exp.failed.Add(uint64(count))
+	exp.mu.Lock()
+	exp.lastErr = err
+	exp.mu.Unlock()
+}
+
+// OTLP/HTTP JSON payload, ids are hex and 64-bit integers are strings
+type otlpRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 otlpValue      `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+	TraceID              string         `json:"traceId,omitempty"`
+	SpanID               string         `json:"spanId,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+// otlpSeverity maps logrus level to OTLP severity number
+func otlpSeverity(level logrus.Level) int {
+	switch level {
+	case logrus.TraceLevel:
+		return 1
+	case logrus.DebugLevel:
+		return 5
+	case logrus.InfoLevel:
+		return 9
+	case logrus.WarnLevel:
+		return 13
+	case logrus.ErrorLevel:
+		return 17
+	case logrus.FatalLevel:
+		return 21
+	default: //panic
+		return 24
+	}
+}
+
+func toOTLPRecord(entry *logrus.Entry) otlpLogRecord {
+	now := strconv.FormatInt(time.Now().UnixNano(), 10)
+	r := otlpLogRecord{
+		TimeUnixNano:         strconv.FormatInt(entry.Time.UnixNano(), 10),
+		ObservedTimeUnixNano: now,
+		SeverityNumber:       otlpSeverity(entry.Level),
+		SeverityText:         otlpSeverityText(entry.Level),
+		Body:                 toOTLPValue(entry.Message),
+	}
+	if sc, ok := SpanFromContext(requestContext(entry.Context)); ok {
+		r.TraceID, r.SpanID = sc.TraceID, sc.SpanID
+	}
+
+	keys := make([]string, 0, len(entry.Data))
+	for k := range entry.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := entry.Data[k]
+		//ids set by fields win over context, invalid ones are kept as attributes
+		//as collector rejects the whole request
+		switch k {
+		case "traceId":
+			if s, ok := v.(string); ok && isOTLPID(s, 16) {
+				r.TraceID = s
+				continue
+			}
+		case "spanId":
+			if s, ok := v.(string); ok && isOTLPID(s, 8) {
+				r.SpanID = s
+				continue
+			}
+		}
+		r.Attributes = append(r.Attributes, otlpKeyValue{k, toOTLPValue(v)})
+	}
+	return r
+}
+
+func otlpSeverityText(level logrus.Level) string {
+	if level == logrus.WarnLevel {
+		return "WARN"
+	}
+	text, _ := level.MarshalText()
+	return string(bytes.ToUpper(text))
+}
+
+func toOTLPValue(v interface{}) otlpValue {
+	switch val := v.(type) {
+	case string:
+		return otlpValue{StringValue: &val}
+	case bool:
+		return otlpValue{BoolValue: &val}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32:
+		s := fmt.Sprint(val)
+		return otlpValue{IntValue: &s}
+	case uint64:
+		s := strconv.FormatUint(val, 10)
+		//intValue is int64
+		if val > math.MaxInt64 {
+			return otlpValue{StringValue: &s}
+		}
+		return otlpValue{IntValue: &s}
+	case float32:
+		return doubleValue(float64(val))
+	case float64:
+		return doubleValue(val)
+	case error:
+		s := val.Error()
+		return otlpValue{StringValue: &s}
+	default:
+		s := fmt.Sprint(val)
+		return otlpValue{StringValue: &s}
+	}
+}
+
+// doubleValue returns stringValue such as NaN and +Inf, which are not supported by json
+func doubleValue(f float64) otlpValue {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		s := strconv.FormatFloat(f, 'g', -1, 64)
+		return otlpValue{StringValue: &s}
+	}
+	return otlpValue{DoubleValue: &f}
+}
+
+// isOTLPID reports whether s is lowercase hex id of size bytes, such as 16 for trace id
+func isOTLPID(s string, size int) bool {
+	if len(s) != size*2 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
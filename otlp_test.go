@@ -0,0 +1,182 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 11:48:36
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 11:48:36
+ * @FilePath: /xlogrus/otlp_test.go
+ * @Description: test OTLP/HTTP JSON exporter with fake collector
+ *
+ */
+
+package xlogrus
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	req "github.com/stretchr/testify/require" //exit if failed
+)
+
+// collected is request body received by fake collector
+type collected struct {
+	contentType string
+	body        otlpRequest
+	err         error
+}
+
+func TestOTLPExporter(t *testing.T) {
+	bodies := make(chan collected, 4)
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		//first request failed to test retry
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		//assert in test goroutine
+		var got collected
+		got.contentType = r.Header.Get("Content-Type")
+		got.err = json.NewDecoder(r.Body).Decode(&got.body)
+		bodies <- got
+	}))
+	defer srv.Close()
+
+	exp, _, err := NewOTLPExporter(
+		WithOTLPEndpoint[OTLPOpt](srv.URL),
+		WithOTLPServiceName[OTLPOpt]("xlogrus-test"),
+		WithOTLPRetryBackoff[OTLPOpt](time.Millisecond),
+		WithOTLPFlushInterval[OTLPOpt](time.Hour),
+	)
+	req.NoError(t, err)
+
+	lg, opt, err := NewUserLog(
+		WithLogPath[UserOpt](t.TempDir()+"/"),
+		WithHooks[UserOpt](exp),
+	)
+	req.NoError(t, err)
+	defer opt.Close()
+	lg.SetOutput(io.Discard)
+
+	ctx := ContextWithSpan(context.Background(), SpanContext{
+		TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:  "00f067aa0ba902b7",
+	})
+	lg.WithContext(ctx).WithFields(logrus.Fields{"rows": 3, "bytes": uint64(7)}).Warn("slow query")
+	exp.Flush()
+	req.NoError(t, exp.Close())
+	close(bodies)
+
+	var got []collected
+	for b := range bodies {
+		got = append(got, b)
+	}
+	req.Len(t, got, 1)
+	req.NoError(t, got[0].err)
+	req.Equal(t, "application/json", got[0].contentType)
+	req.Equal(t, int32(2), calls.Load())
+	rl := got[0].body.ResourceLogs[0]
+	req.Equal(t, "service.name", rl.Resource.Attributes[0].Key)
+	req.Equal(t, "xlogrus-test", *rl.Resource.Attributes[0].Value.StringValue)
+	record := rl.ScopeLogs[0].LogRecords[0]
+	req.Equal(t, 13, record.SeverityNumber)
+	req.Equal(t, "WARN", record.SeverityText)
+	req.Equal(t, "slow query", *record.Body.StringValue)
+	req.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record.TraceID)
+	req.Equal(t, "00f067aa0ba902b7", record.SpanID)
+	req.Equal(t, "bytes", record.Attributes[0].Key)
+	req.Equal(t, "7", *record.Attributes[0].Value.IntValue)
+	req.Equal(t, "rows", record.Attributes[1].Key)
+	req.Equal(t, "3", *record.Attributes[1].Value.IntValue)
+}
+
+func TestOTLPValue(t *testing.T) {
+	req.Equal(t, "18446744073709551615", *toOTLPValue(uint64(math.MaxUint64)).StringValue)
+	req.Equal(t, "9223372036854775807", *toOTLPValue(uint64(math.MaxInt64)).IntValue)
+	//json does not support non-finite floats
+	req.Equal(t, "NaN", *toOTLPValue(math.NaN()).StringValue)
+	req.Equal(t, "+Inf", *toOTLPValue(math.Inf(1)).StringValue)
+	req.Equal(t, "-Inf", *toOTLPValue(float32(math.Inf(-1))).StringValue)
+	req.Equal(t, 0.5, *toOTLPValue(0.5).DoubleValue)
+}
+
+func TestOTLPExporterInvalidValues(t *testing.T) {
+	bodies := make(chan collected, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var got collected
+		got.err = json.NewDecoder(r.Body).Decode(&got.body)
+		bodies <- got
+	}))
+	defer srv.Close()
+
+	exp, _, err := NewOTLPExporter(
+		WithOTLPEndpoint[OTLPOpt](srv.URL),
+		WithOTLPFlushInterval[OTLPOpt](time.Hour),
+	)
+	req.NoError(t, err)
+	lg := logrus.New()
+	lg.SetOutput(io.Discard)
+	lg.AddHook(exp)
+	lg.Info("good")
+	lg.WithField("ratio", math.NaN()).Info("bad float")
+	lg.WithFields(logrus.Fields{"traceId": "not-a-trace", "spanId": "00F067AA0BA902B7"}).Info("bad ids")
+	exp.Flush()
+	req.NoError(t, exp.Close())
+	close(bodies)
+
+	var got []collected
+	for b := range bodies {
+		got = append(got, b)
+	}
+	req.Len(t, got, 1)
+	req.NoError(t, got[0].err)
+	req.Zero(t, exp.Failed())
+	records := got[0].body.ResourceLogs[0].ScopeLogs[0].LogRecords
+	req.Len(t, records, 3)
+	req.Equal(t, "NaN", *records[1].Attributes[0].Value.StringValue)
+	//invalid ids are kept as attributes
+	req.Empty(t, records[2].TraceID)
+	req.Empty(t, records[2].SpanID)
+	req.Equal(t, "spanId", records[2].Attributes[0].Key)
+	req.Equal(t, "00F067AA0BA902B7", *records[2].Attributes[0].Value.StringValue)
+	req.Equal(t, "traceId", records[2].Attributes[1].Key)
+	req.Equal(t, "not-a-trace", *records[2].Attributes[1].Value.StringValue)
+}
+
+func TestOTLPExporterClose(t *testing.T) {
+	calls := make(chan struct{}, 16)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls <- struct{}{}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	exp, _, err := NewOTLPExporter(
+		WithOTLPEndpoint[OTLPOpt](srv.URL),
+		WithOTLPRetryBackoff[OTLPOpt](time.Minute),
+		WithOTLPFlushInterval[OTLPOpt](time.Millisecond),
+	)
+	req.NoError(t, err)
+	lg := logrus.New()
+	lg.SetOutput(io.Discard)
+	lg.AddHook(exp)
+	lg.Info("retrying")
+
+	//wait until export is in backoff
+	select {
+	case <-calls:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no export")
+	}
+	start := time.Now()
+	req.Error(t, exp.Close())
+	req.Less(t, time.Since(start), time.Second, "close is blocked by backoff")
+	req.Equal(t, uint64(1), exp.Failed())
+}
@@ -0,0 +1,168 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 20:05:48
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 20:05:48
+ * @FilePath: /xlogrus/redact.go
+ * @Description: redact sensitive words by banned keywords
+ *
+ */
+
+package xlogrus
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// mask for redacted value
+const redactedValue = "***"
+
+// defaultBKeywords returns banned keywords used by default
+func defaultBKeywords() []BannedKeyword {
+	return []BannedKeyword{
+		{
+			"password",
+			false,
+		},
+		{
+			"pwd",
+			false,
+		},
+	}
+}
+
+/*ignoreBKeyword
+ * @msg deal with sensitive word, and replaced that line with "ignore line with banned word..."
+ * @param keywords
+ * @param lContent
+ * @return: string
+ */
+func ignoreBKeyword(keywords []BannedKeyword, lContent string) string {
+	if len(keywords) <= 0 {
+		return lContent
+	}
+	arrLine := strings.Split(strings.Trim(lContent, "\n"), "\n")
+	for idx := 0; idx < len(keywords); idx++ {
+		for i := 0; i < len(arrLine); i++ {
+			if keywords[idx].IsCaseSensitive &&
+				strings.Contains(arrLine[i], keywords[idx].Keyword) {
+				//found with case-sensitive
+				arrLine[i] = fmt.Sprintf("ignored line with banned word %v",
+					keywords[idx].Keyword)
+			} else if !keywords[idx].IsCaseSensitive &&
+				strings.Contains(
+					strings.ToLower(arrLine[i]),
+					strings.ToLower(keywords[idx].Keyword),
+				) { //found with ignore case-sensitive
+				arrLine[i] = fmt.Sprintf("ignored line with banned word: %v",
+					keywords[idx].Keyword)
+			}
+		}
+	}
+	return strings.Join(arrLine, "\n")
+}
+
+// hasBKeyword reports whether s contains any banned keyword
+func hasBKeyword(keywords []BannedKeyword, s string) bool {
+	for _, k := range keywords {
+		if k.IsCaseSensitive && strings.Contains(s, k.Keyword) ||
+			!k.IsCaseSensitive && strings.Contains(strings.ToLower(s), strings.ToLower(k.Keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
+/*redactJSON
+ * @msg mask values whose key contains banned keyword, such as {"password":"***"}
+ * @param keywords
+ * @param body
+ * @return: string
+ * @return: bool false if body is not valid json
+ */
+func redactJSON(keywords []BannedKeyword, body []byte) (string, bool) {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return "", false
+	}
+	b, err := json.Marshal(redactValue(keywords, v))
+	if err != nil {
+		return "", false
+	}
+	return string(b), true
+}
+
+func redactValue(keywords []BannedKeyword, v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, sub := range val {
+			if hasBKeyword(keywords, k) {
+				val[k] = redactedValue
+			} else {
+				val[k] = redactValue(keywords, sub)
+			}
+		}
+	case []interface{}:
+		for i, sub := range val {
+			val[i] = redactValue(keywords, sub)
+		}
+	}
+	return v
+}
+
+// redactForm masks values whose key contains banned keyword in urlencoded form
+func redactForm(keywords []BannedKeyword, body string) (string, bool) {
+	values, err := url.ParseQuery(body)
+	if err != nil {
+		return "", false
+	}
+	return encodeMasked(values, func(k string) bool { return hasBKeyword(keywords, k) }), true
+}
+
+// encodeMasked encodes values sorted by key like url.Values.Encode, masked values are not escaped
+func encodeMasked(values url.Values, mask func(string) bool) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		for _, v := range values[k] {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(url.QueryEscape(k))
+			b.WriteByte('=')
+			if mask(k) {
+				b.WriteString(redactedValue)
+			} else {
+				b.WriteString(url.QueryEscape(v))
+			}
+		}
+	}
+	return b.String()
+}
+
+// redactBody redacts json or form body, or ignores lines with banned keyword for others
+func redactBody(keywords []BannedKeyword, contentType string, body []byte) string {
+	if len(keywords) == 0 {
+		return string(body)
+	}
+	switch {
+	case strings.Contains(contentType, "json"):
+		if s, ok := redactJSON(keywords, body); ok {
+			return s
+		}
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		if s, ok := redactForm(keywords, string(body)); ok {
+			return s
+		}
+	}
+	//truncated or unknown body
+	return ignoreBKeyword(keywords, string(body))
+}
@@ -0,0 +1,130 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 15:02:18
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 15:02:18
+ * @FilePath: /xlogrus/slog_handler.go
+ * @Description: log/slog handler backed by xlogrus logger
+ *
+ */
+
+package xlogrus
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*SlogHandler
+ * @msg slog.Handler writes to xlogrus logger, so slog shares rotated files,
+ *		error.log and hooks with logrus. attributes are flattened into
+ *		fields, group name is used as prefix such as "req.method"
+ */
+type SlogHandler struct {
+	lg     *TLogrus
+	fields logrus.Fields
+	prefix string //group prefix ending with "."
+}
+
+// NewSlogHandler creates handler for logger from NewUserLog/NewGinLog, use it by slog.New(h)
+func NewSlogHandler(lg *TLogrus) *SlogHandler {
+	return &SlogHandler{lg: lg, fields: logrus.Fields{}}
+}
+
+/*SlogLevel
+ * @msg map slog level to logrus level
+ *		< debug: trace, < info: debug, < warn: info, < error: warn, >= error: error
+ */
+func SlogLevel(level slog.Level) logrus.Level {
+	switch {
+	case level < slog.LevelDebug:
+		return logrus.TraceLevel
+	case level < slog.LevelInfo:
+		return logrus.DebugLevel
+	case level < slog.LevelWarn:
+		return logrus.InfoLevel
+	case level < slog.LevelError:
+		return logrus.WarnLevel
+	default:
+		return logrus.ErrorLevel
+	}
+}
+
+// Enabled implements slog.Handler
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.lg.IsLevelEnabled(SlogLevel(level))
+}
+
+// Handle implements slog.Handler
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	fields := make(logrus.Fields, len(h.fields)+r.NumAttrs())
+	for k, v := range h.fields {
+		fields[k] = v
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		addSlogAttr(fields, h.prefix, a)
+		return true
+	})
+	entry := h.lg.WithContext(ctx).WithFields(fieldsFromContext(ctx)).WithFields(fields)
+	if !r.Time.IsZero() {
+		entry = entry.WithTime(r.Time)
+	}
+	entry.Log(SlogLevel(r.Level), r.Message)
+	return nil
+}
+
+// WithAttrs implements slog.Handler
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := h.clone()
+	for _, a := range attrs {
+		addSlogAttr(h2.fields, h2.prefix, a)
+	}
+	return h2
+}
+
+// WithGroup implements slog.Handler
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := h.clone()
+	h2.prefix += name + "."
+	return h2
+}
+
+func (h *SlogHandler) clone() *SlogHandler {
+	fields := make(logrus.Fields, len(h.fields))
+	for k, v := range h.fields {
+		fields[k] = v
+	}
+	return &SlogHandler{lg: h.lg, fields: fields, prefix: h.prefix}
+}
+
+// addSlogAttr flattens attr into fields, group attrs are added with prefix
+func addSlogAttr(fields logrus.Fields, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	//ignore empty attr as slog.Handler requires
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		group := a.Value.Group()
+		if len(group) == 0 {
+			return
+		}
+		//inline group if key is empty
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range group {
+			addSlogAttr(fields, prefix, ga)
+		}
+		return
+	}
+	fields[prefix+a.Key] = a.Value.Any()
+}
@@ -0,0 +1,44 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 15:27:09
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 15:27:09
+ * @FilePath: /xlogrus/slog_handler_test.go
+ * @Description: test slog handler backed by user log
+ *
+ */
+
+package xlogrus
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	lTest "github.com/sirupsen/logrus/hooks/test" //logrus tools for test
+	req "github.com/stretchr/testify/require"     //exit if failed
+)
+
+func TestSlogHandler(t *testing.T) {
+	lg, opt, err := NewUserLog(
+		WithLogPath[UserOpt](t.TempDir()+"/"),
+		WithLogLevel[UserOpt]("info"),
+	)
+	req.NoError(t, err)
+	defer opt.Close()
+	lg.SetOutput(io.Discard)
+	hook := lTest.NewLocal(lg)
+
+	sl := slog.New(NewSlogHandler(lg)).With("app", "demo").WithGroup("req")
+	sl.Debug("dropped")
+	req.Nil(t, hook.LastEntry())
+
+	sl.Warn("slow request", "method", "GET", slog.Group("user", "id", 7))
+	entry := hook.LastEntry()
+	req.Equal(t, logrus.WarnLevel, entry.Level)
+	req.Equal(t, "slow request", entry.Message)
+	req.Equal(t, logrus.Fields{"app": "demo", "req.method": "GET", "req.user.id": int64(7)}, entry.Data)
+	req.Equal(t, logrus.ErrorLevel, SlogLevel(slog.LevelError+4))
+	req.Equal(t, logrus.TraceLevel, SlogLevel(slog.LevelDebug-4))
+}
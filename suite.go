@@ -0,0 +1,193 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 17:40:03
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 17:40:03
+ * @FilePath: /xlogrus/suite.go
+ * @Description: create user, gin and gorm logs with shared options
+ *
+ */
+
+package xlogrus
+
+import (
+	"github.com/gin-gonic/gin"
+	c "github.com/justin-ren/xlogrus/common"
+	"github.com/pkg/errors"
+)
+
+// BaseOpt is options shared by user/gin/gorm logs, such as WithLogPath[BaseOpt]
+type BaseOpt = c.OptLog
+
+type SuiteOpt struct {
+	//applied to all logs before their own options
+	Base []c.LogOption[BaseOpt]
+	User []c.LogOption[UserOpt]
+	Gin  []c.LogOption[GinOpt]
+	Gorm []c.LogOption[GormOpt]
+}
+
+// SetBase appends options shared by all logs.
+func (o *SuiteOpt) SetBase(opts ...c.LogOption[BaseOpt]) error {
+	o.Base = append(o.Base, opts...)
+	return nil
+}
+
+// SetUser appends options for user log.
+func (o *SuiteOpt) SetUser(opts ...c.LogOption[UserOpt]) error {
+	o.User = append(o.User, opts...)
+	return nil
+}
+
+// SetGin appends options for gin log.
+func (o *SuiteOpt) SetGin(opts ...c.LogOption[GinOpt]) error {
+	o.Gin = append(o.Gin, opts...)
+	return nil
+}
+
+// SetGorm appends options for gorm log.
+func (o *SuiteOpt) SetGorm(opts ...c.LogOption[GormOpt]) error {
+	o.Gorm = append(o.Gorm, opts...)
+	return nil
+}
+
+// WithBase 设置所有日志共用的选项
+func WithBase[
+	T any,
+	PT interface {
+		*T
+		SetBase(...c.LogOption[BaseOpt]) error
+	},
+](opts ...c.LogOption[BaseOpt]) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetBase(opts...)
+	})
+}
+
+// WithUser 设置user日志的选项，覆盖共用选项
+func WithUser[
+	T any,
+	PT interface {
+		*T
+		SetUser(...c.LogOption[UserOpt]) error
+	},
+](opts ...c.LogOption[UserOpt]) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetUser(opts...)
+	})
+}
+
+// WithGin 设置gin日志的选项，覆盖共用选项
+func WithGin[
+	T any,
+	PT interface {
+		*T
+		SetGin(...c.LogOption[GinOpt]) error
+	},
+](opts ...c.LogOption[GinOpt]) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetGin(opts...)
+	})
+}
+
+// WithGorm 设置gorm日志的选项，覆盖共用选项
+func WithGorm[
+	T any,
+	PT interface {
+		*T
+		SetGorm(...c.LogOption[GormOpt]) error
+	},
+](opts ...c.LogOption[GormOpt]) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetGorm(opts...)
+	})
+}
+
+// GinSuite is gin log and its middleware
+type GinSuite struct {
+	Logger     *TLogrus
+	Middleware gin.HandlerFunc
+	Opt        *GinOpt
+}
+
+/*Suite
+ * @msg user, gin and gorm logs sharing writers, such as error.log
+ */
+type Suite struct {
+	User    *TLogrus
+	UserOpt *UserOpt
+	Gin     GinSuite
+	Gorm    *GormLog
+	GormOpt *GormOpt
+
+	pool *c.WriterPool
+}
+
+/*New
+ * @msg create user, gin and gorm logs, options are applied in order:
+ *		default values, WithBase, WithUser/WithGin/WithGorm
+ * @param setFunc
+ * @return: *Suite
+ * @return: error
+ */
+func New(setFunc ...c.LogOption[SuiteOpt]) (*Suite, error) {
+	opt := &SuiteOpt{}
+	for _, f := range setFunc {
+		if err := f.Apply(opt); err != nil {
+			return nil, errors.Wrap(err, "failed to apply suite option")
+		}
+	}
+
+	s := &Suite{pool: c.NewWriterPool()}
+	var err error
+	if s.User, s.UserOpt, err = NewUserLog(
+		append([]c.LogOption[UserOpt]{baseOption[UserOpt](s.pool, opt.Base)}, opt.User...)...,
+	); err != nil {
+		s.Close()
+		return nil, errors.Wrap(err, "failed to create user log")
+	}
+	if s.Gin.Logger, s.Gin.Middleware, s.Gin.Opt, err = NewGinLog(
+		append([]c.LogOption[GinOpt]{baseOption[GinOpt](s.pool, opt.Base)}, opt.Gin...)...,
+	); err != nil {
+		s.Close()
+		return nil, errors.Wrap(err, "failed to create gin log")
+	}
+	if s.Gorm, s.GormOpt, err = NewGormLog(
+		append([]c.LogOption[GormOpt]{baseOption[GormOpt](s.pool, opt.Base)}, opt.Gorm...)...,
+	); err != nil {
+		s.Close()
+		return nil, errors.Wrap(err, "failed to create gorm log")
+	}
+	return s, nil
+}
+
+// Close closes all log files of suite.
+func (s *Suite) Close() error {
+	return s.pool.Close()
+}
+
+// baseOption applies shared options and writer pool to embedded BaseOpt
+func baseOption[
+	T any,
+	PT interface {
+		*T
+		base() *BaseOpt
+	},
+](pool *c.WriterPool, base []c.LogOption[BaseOpt]) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		opt := PT(t).base()
+		if err := opt.SetWriterPool(pool); err != nil {
+			return err
+		}
+		for _, f := range base {
+			if err := f.Apply(opt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (o *UserOpt) base() *BaseOpt { return o.OptLog }
+func (o *GinOpt) base() *BaseOpt  { return o.OptLog }
+func (o *GormOpt) base() *BaseOpt { return o.OptLog }
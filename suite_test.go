@@ -0,0 +1,56 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 18:05:47
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 18:05:47
+ * @FilePath: /xlogrus/suite_test.go
+ * @Description: test user, gin and gorm logs created by one suite
+ *
+ */
+
+package xlogrus
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	req "github.com/stretchr/testify/require" //exit if failed
+)
+
+func TestSuite(t *testing.T) {
+	path := t.TempDir() + "/"
+	s, err := New(
+		WithBase[SuiteOpt](
+			WithLogPath[BaseOpt](path),
+			WithLogLevel[BaseOpt]("info"),
+		),
+		WithUser[SuiteOpt](WithLogLevel[UserOpt]("debug")),
+		WithGorm[SuiteOpt](WithGormLogLevel[GormOpt]("error")),
+	)
+	req.NoError(t, err)
+	defer s.Close()
+
+	for _, lg := range []*TLogrus{s.User, s.Gin.Logger, s.Gorm.Logger} {
+		lg.SetOutput(io.Discard)
+	}
+	req.NotNil(t, s.Gin.Middleware)
+	req.Equal(t, path, s.Gin.Opt.LogPath)
+	req.Equal(t, logrus.DebugLevel, s.User.GetLevel())
+	req.Equal(t, logrus.InfoLevel, s.Gin.Logger.GetLevel())
+	req.Equal(t, "db.log", s.GormOpt.FileNamePrefix)
+
+	//error.log is shared
+	userWriters, gormWriters := s.UserOpt.Writers(), s.GormOpt.Writers()
+	req.Len(t, userWriters, 2)
+	req.Same(t, userWriters[1], gormWriters[1])
+	req.NotSame(t, userWriters[0], gormWriters[0])
+
+	s.User.Error("user error")
+	s.Gorm.Error(context.Background(), "gorm error")
+	req.NoError(t, s.Close())
+
+	_, err = New(WithBase[SuiteOpt](WithLogLevel[BaseOpt]("verbose")))
+	req.Error(t, err)
+}
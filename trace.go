@@ -0,0 +1,126 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 11:02:47
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 11:02:47
+ * @FilePath: /xlogrus/trace.go
+ * @Description: trace and span ids carried by context.Context
+ *
+ */
+
+package xlogrus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// SpanContext is trace id and span id in lower-case hex, such as W3C traceparent
+type SpanContext struct {
+	//32 hex chars
+	TraceID string
+	//16 hex chars
+	SpanID string
+	//span id of caller, empty for root span
+	ParentSpanID string
+	//trace flags such as "01" for sampled, "01" is used if empty
+	TraceFlags string
+	//W3C tracestate passed through
+	TraceState string
+}
+
+// IsValid reports whether both trace id and span id are set.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != "" && sc.SpanID != ""
+}
+
+type spanCtxKey struct{}
+
+// ContextWithSpan returns a copy of ctx carrying sc.
+func ContextWithSpan(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanCtxKey{}, sc)
+}
+
+// SpanFromContext returns the span context carried by ctx.
+func SpanFromContext(ctx context.Context) (SpanContext, bool) {
+	if ctx == nil {
+		return SpanContext{}, false
+	}
+	sc, ok := ctx.Value(spanCtxKey{}).(SpanContext)
+	return sc, ok
+}
+
+// Traceparent formats sc as W3C traceparent header value.
+func (sc SpanContext) Traceparent() string {
+	flags := sc.TraceFlags
+	if flags == "" {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", sc.TraceID, sc.SpanID, flags)
+}
+
+/*InjectTraceparent
+ * @msg set traceparent and tracestate of span in ctx to outgoing request header,
+ *		such as InjectTraceparent(ctx, request.Header)
+ * @param ctx
+ * @param header
+ * @return: bool false if no span in ctx
+ */
+func InjectTraceparent(ctx context.Context, header http.Header) bool {
+	sc, ok := SpanFromContext(requestContext(ctx))
+	if !ok || !sc.IsValid() {
+		return false
+	}
+	header.Set("traceparent", sc.Traceparent())
+	if sc.TraceState != "" {
+		header.Set("tracestate", sc.TraceState)
+	}
+	return true
+}
+
+/*StartSpan
+ * @msg start server span from W3C traceparent/tracestate header, a new trace
+ *		is started if traceparent is missing or invalid
+ * @param header incoming request header
+ * @return: SpanContext with new span id and caller span id as parent
+ */
+func StartSpan(header http.Header) SpanContext {
+	parent, ok := parseTraceparent(header.Get("traceparent"))
+	if !ok {
+		return SpanContext{TraceID: NewTraceID(), SpanID: NewSpanID(), TraceFlags: "01"}
+	}
+	return SpanContext{
+		TraceID:      parent.TraceID,
+		SpanID:       NewSpanID(),
+		ParentSpanID: parent.SpanID,
+		TraceFlags:   parent.TraceFlags,
+		TraceState:   strings.TrimSpace(header.Get("tracestate")),
+	}
+}
+
+// NewTraceID returns random non-zero trace id with 32 hex chars
+func NewTraceID() string {
+	return randomHex(16)
+}
+
+// NewSpanID returns random non-zero span id with 16 hex chars
+func NewSpanID() string {
+	return randomHex(8)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	for {
+		_, _ = rand.Read(b)
+		for _, v := range b {
+			//all zero is invalid
+			if v != 0 {
+				return hex.EncodeToString(b)
+			}
+		}
+	}
+}
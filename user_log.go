@@ -22,6 +22,7 @@ type UserOpt struct {
 func GetUserOpt() *UserOpt {
 	opt := UserOpt{c.InitOpt()}
 	opt.FileNamePrefix = "trace.log"
+	opt.LoggerName = "user"
 	return &opt
 }
 
@@ -0,0 +1,358 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 11:20:44
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 11:20:44
+ * @FilePath: /xlogrus/useragent.go
+ * @Description: dependency-free user agent parser with LRU cache
+ *
+ */
+
+package xlogrus
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+)
+
+// user agent longer than it is parsed but not cached
+const maxCachedUserAgent = 512
+
+// device of user agent
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceBot     = "bot"
+	DeviceOther   = "other"
+)
+
+// UserAgent is parsed result of User-Agent header
+type UserAgent struct {
+	//such as Chrome, Firefox, Googlebot, curl
+	Browser string
+	//version of browser such as 120.0.6099.109
+	Version string
+	//such as Windows 10, macOS 10.15.7, iOS 17.1, Android 14
+	OS string
+	//desktop, mobile, tablet, bot or other
+	Device string
+	//crawlers, health checkers and monitors
+	IsBot bool
+}
+
+// known bots in lower case, the token is also used as browser name if it's matched
+var knownBots = []struct {
+	token string
+	name  string
+}{
+	{"googlebot", "Googlebot"},
+	{"bingbot", "Bingbot"},
+	{"yandexbot", "YandexBot"},
+	{"baiduspider", "Baiduspider"},
+	{"duckduckbot", "DuckDuckBot"},
+	{"applebot", "Applebot"},
+	{"slurp", "Yahoo Slurp"},
+	{"facebookexternalhit", "Facebook"},
+	{"kube-probe", "kube-probe"},
+	{"elb-healthchecker", "ELB-HealthChecker"},
+	{"googlehc", "GoogleHC"},
+	{"consul health check", "Consul"},
+	{"uptimerobot", "UptimeRobot"},
+	{"pingdom", "Pingdom"},
+	{"prometheus", "Prometheus"},
+	{"blackbox-exporter", "Blackbox Exporter"},
+}
+
+// how generic bot token is matched
+const (
+	botAnywhere = iota //anywhere in user agent
+	botProduct         //end of product name such as AhrefsBot/7.0, (compatible; PetalBot;) or a word
+	botWord            //a word such as "Uptime Monitor/1.0", but not HeartMonitor/3.2
+)
+
+// generic bot tokens in lower case checked after known ones, they are matched on
+// boundary to avoid devices and apps such as CUBOT J3 or HeartMonitor
+var genericBots = []struct {
+	token string
+	match int
+}{
+	{"bot", botProduct},
+	{"spider", botProduct},
+	{"crawler", botProduct},
+	{"healthcheck", botAnywhere},
+	{"health-check", botAnywhere},
+	{"monitor", botWord},
+}
+
+// browsers and tools checked in order, such as Edge before Chrome before Safari
+var knownBrowsers = []struct {
+	token string
+	name  string
+}{
+	{"Edg/", "Edge"},
+	{"Edge/", "Edge"},
+	{"EdgiOS/", "Edge"},
+	{"OPR/", "Opera"},
+	{"SamsungBrowser/", "Samsung Internet"},
+	{"YaBrowser/", "Yandex Browser"},
+	{"Firefox/", "Firefox"},
+	{"FxiOS/", "Firefox"},
+	{"CriOS/", "Chrome"},
+	{"Chromium/", "Chromium"},
+	{"Chrome/", "Chrome"},
+	{"curl/", "curl"},
+	{"Wget/", "Wget"},
+	{"PostmanRuntime/", "Postman"},
+	{"python-requests/", "python-requests"},
+	{"Go-http-client/", "Go-http-client"},
+	{"okhttp/", "okhttp"},
+	{"Java/", "Java"},
+}
+
+/*UserAgentParser
+ * @msg parse User-Agent by known tokens, results are cached in LRU
+ *		as the same user agents are repeated in access log
+ */
+type UserAgentParser struct {
+	//extra bot tokens in lower case
+	bots []string
+
+	mu    sync.Mutex
+	size  int
+	items map[string]*list.Element
+	lru   *list.List
+}
+
+type uaItem struct {
+	key string
+	ua  UserAgent
+}
+
+/*NewUserAgentParser
+ * @msg create parser with LRU cache
+ * @param size max cached user agents, cache is disabled if <= 0
+ * @param bots extra tokens of bots such as "my-health-checker", case insensitive
+ * @return: *UserAgentParser
+ */
+func NewUserAgentParser(size int, bots ...string) *UserAgentParser {
+	p := &UserAgentParser{size: size, items: make(map[string]*list.Element), lru: list.New()}
+	for _, b := range bots {
+		if b = strings.ToLower(strings.TrimSpace(b)); b != "" {
+			p.bots = append(p.bots, b)
+		}
+	}
+	return p
+}
+
+// Parse parses user agent, cached result is returned if found.
+func (p *UserAgentParser) Parse(s string) UserAgent {
+	if p.size <= 0 || len(s) > maxCachedUserAgent {
+		return p.parse(s)
+	}
+	p.mu.Lock()
+	if el, ok := p.items[s]; ok {
+		p.lru.MoveToFront(el)
+		ua := el.Value.(*uaItem).ua
+		p.mu.Unlock()
+		return ua
+	}
+	p.mu.Unlock()
+
+	ua := p.parse(s)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.items[s]; !ok {
+		p.items[s] = p.lru.PushFront(&uaItem{key: s, ua: ua})
+		if p.lru.Len() > p.size {
+			oldest := p.lru.Back()
+			p.lru.Remove(oldest)
+			delete(p.items, oldest.Value.(*uaItem).key)
+		}
+	}
+	return ua
+}
+
+// Len returns count of cached user agents.
+func (p *UserAgentParser) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lru.Len()
+}
+
+// ParseUserAgent parses user agent without cache
+func ParseUserAgent(s string) UserAgent {
+	return (&UserAgentParser{}).parse(s)
+}
+
+func (p *UserAgentParser) parse(s string) UserAgent {
+	var ua UserAgent
+	if strings.TrimSpace(s) == "" {
+		return ua
+	}
+	lower := strings.ToLower(s)
+	ua.Browser, ua.Version = parseBrowser(s)
+	ua.OS = parseOS(s)
+	if name, ok := p.matchBot(s, lower); ok {
+		ua.IsBot = true
+		if name != "" {
+			ua.Browser, ua.Version = name, tokenVersion(s, name)
+		}
+	}
+	ua.Device = parseDevice(s, ua)
+	return ua
+}
+
+// matchBot returns name of known bot, empty name for generic bots
+func (p *UserAgentParser) matchBot(s, lower string) (string, bool) {
+	for _, b := range p.bots {
+		if strings.Contains(lower, b) {
+			return "", true
+		}
+	}
+	for _, b := range knownBots {
+		if strings.Contains(lower, b.token) {
+			return b.name, true
+		}
+	}
+	for _, b := range genericBots {
+		if matchBotToken(lower, b.token, b.match) {
+			return "", true
+		}
+	}
+	return "", false
+}
+
+// matchBotToken reports whether token is found in s on boundary of match mode
+func matchBotToken(s, token string, match int) bool {
+	for i := 0; ; {
+		j := strings.Index(s[i:], token)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(token)
+		switch {
+		case match == botAnywhere:
+			return true
+		case isWordBoundary(s, start-1) && isWordBoundary(s, end):
+			return true
+		case match == botProduct && (end == len(s) || strings.IndexByte("/;),", s[end]) >= 0):
+			return true
+		}
+		i = end
+	}
+}
+
+// isWordBoundary reports whether s[i] is out of range or not a letter or digit
+func isWordBoundary(s string, i int) bool {
+	if i < 0 || i >= len(s) {
+		return true
+	}
+	ch := s[i]
+	return !(ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9')
+}
+
+func parseBrowser(s string) (string, string) {
+	for _, b := range knownBrowsers {
+		if i := strings.Index(s, b.token); i >= 0 {
+			return b.name, readVersion(s[i+len(b.token):])
+		}
+	}
+	switch {
+	case strings.Contains(s, "Safari/") && strings.Contains(s, "Version/"):
+		return "Safari", tokenVersion(s, "Version")
+	case strings.Contains(s, "MSIE "):
+		return "IE", readVersion(s[strings.Index(s, "MSIE ")+5:])
+	case strings.Contains(s, "Trident/"):
+		return "IE", tokenVersion(s, "rv:")
+	}
+	return "", ""
+}
+
+func parseOS(s string) string {
+	switch {
+	case strings.Contains(s, "Windows NT "):
+		return windowsName(readVersion(s[strings.Index(s, "Windows NT ")+11:]))
+	case strings.Contains(s, "iPhone") || strings.Contains(s, "iPad") || strings.Contains(s, "iPod"):
+		if i := strings.Index(s, " OS "); i >= 0 {
+			return withVersion("iOS", readVersion(strings.ReplaceAll(s[i+4:], "_", ".")))
+		}
+		return "iOS"
+	case strings.Contains(s, "Android"):
+		if i := strings.Index(s, "Android "); i >= 0 {
+			return withVersion("Android", readVersion(s[i+8:]))
+		}
+		return "Android"
+	case strings.Contains(s, "CrOS"):
+		return "Chrome OS"
+	case strings.Contains(s, "Mac OS X"):
+		i := strings.Index(s, "Mac OS X")
+		return withVersion("macOS", readVersion(strings.ReplaceAll(strings.TrimLeft(s[i+8:], " "), "_", ".")))
+	case strings.Contains(s, "Windows"):
+		return "Windows"
+	case strings.Contains(s, "Linux"):
+		return "Linux"
+	}
+	return ""
+}
+
+func parseDevice(s string, ua UserAgent) string {
+	switch {
+	case ua.IsBot:
+		return DeviceBot
+	case strings.Contains(s, "iPad") || strings.Contains(s, "Tablet") ||
+		strings.Contains(s, "Android") && !strings.Contains(s, "Mobile"):
+		return DeviceTablet
+	case strings.Contains(s, "Mobi") || strings.Contains(s, "iPhone") || strings.Contains(s, "iPod"):
+		return DeviceMobile
+	case strings.HasPrefix(ua.OS, "Windows") || strings.HasPrefix(ua.OS, "macOS") ||
+		ua.OS == "Linux" || ua.OS == "Chrome OS":
+		return DeviceDesktop
+	}
+	return DeviceOther
+}
+
+func windowsName(nt string) string {
+	switch nt {
+	case "10.0":
+		//windows 11 also sends 10.0
+		return "Windows 10"
+	case "6.3":
+		return "Windows 8.1"
+	case "6.2":
+		return "Windows 8"
+	case "6.1":
+		return "Windows 7"
+	}
+	return "Windows"
+}
+
+// tokenVersion returns version after token such as "Version/17.1" or "rv:11.0"
+func tokenVersion(s, token string) string {
+	i := strings.Index(s, token)
+	if i < 0 {
+		return ""
+	}
+	rest := s[i+len(token):]
+	if strings.HasPrefix(rest, "/") || strings.HasPrefix(rest, " ") {
+		rest = rest[1:]
+	}
+	return readVersion(rest)
+}
+
+// readVersion reads leading digits and dots
+func readVersion(s string) string {
+	end := 0
+	for end < len(s) && (s[end] >= '0' && s[end] <= '9' || s[end] == '.') {
+		end++
+	}
+	return strings.TrimRight(s[:end], ".")
+}
+
+func withVersion(name, version string) string {
+	if version == "" {
+		return name
+	}
+	return name + " " + version
+}
@@ -0,0 +1,87 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 12:14:37
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 12:14:37
+ * @FilePath: /xlogrus/useragent_test.go
+ * @Description: test user agent parser
+ *
+ */
+
+package xlogrus
+
+import (
+	"strings"
+	"testing"
+
+	req "github.com/stretchr/testify/require" //exit if failed
+)
+
+func TestParseUserAgent(t *testing.T) {
+	tests := []struct {
+		ua   string
+		want UserAgent
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.109 Safari/537.36",
+			UserAgent{"Chrome", "120.0.6099.109", "Windows 10", DeviceDesktop, false}},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91",
+			UserAgent{"Edge", "120.0.2210.91", "Windows 10", DeviceDesktop, false}},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15",
+			UserAgent{"Safari", "17.1", "macOS 10.15.7", DeviceDesktop, false}},
+		{"Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			UserAgent{"Firefox", "121.0", "Linux", DeviceDesktop, false}},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1",
+			UserAgent{"Safari", "17.1", "iOS 17.1.2", DeviceMobile, false}},
+		{"Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/119.0.6045.169 Mobile/15E148 Safari/604.1",
+			UserAgent{"Chrome", "119.0.6045.169", "iOS 16.6", DeviceTablet, false}},
+		{"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.43 Mobile Safari/537.36",
+			UserAgent{"Chrome", "120.0.6099.43", "Android 14", DeviceMobile, false}},
+		{"Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Safari/537.36",
+			UserAgent{"Samsung Internet", "23.0", "Android 13", DeviceTablet, false}},
+		{"Mozilla/5.0 (Windows NT 6.1; Trident/7.0; rv:11.0) like Gecko",
+			UserAgent{"IE", "11.0", "Windows 7", DeviceDesktop, false}},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			UserAgent{"Googlebot", "2.1", "", DeviceBot, true}},
+		{"kube-probe/1.28",
+			UserAgent{"kube-probe", "1.28", "", DeviceBot, true}},
+		{"Mozilla/5.0 (compatible; SomeNewBot/0.1)",
+			UserAgent{"", "", "", DeviceBot, true}},
+		{"Mozilla/5.0 (compatible; AhrefsBot/7.0; +http://ahrefs.com/robot/)",
+			UserAgent{"", "", "", DeviceBot, true}},
+		{"Uptime Monitor/1.0",
+			UserAgent{"", "", "", DeviceBot, true}},
+		{"SiteCrawler/1.0",
+			UserAgent{"", "", "", DeviceBot, true}},
+		//generic tokens inside device or app names
+		{"Mozilla/5.0 (Linux; Android 9; CUBOT J3 Build/PPR1) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/100.0.4896.79 Mobile Safari/537.36",
+			UserAgent{"Chrome", "100.0.4896.79", "Android 9", DeviceMobile, false}},
+		{"HeartMonitor/3.2 CFNetwork/1410.0.3 Darwin/22.6.0",
+			UserAgent{"", "", "", DeviceOther, false}},
+		{"Mozilla/5.0 (compatible; Crawlspace/2.0)",
+			UserAgent{"", "", "", DeviceOther, false}},
+		{"curl/8.4.0",
+			UserAgent{"curl", "8.4.0", "", DeviceOther, false}},
+		{"", UserAgent{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ua, func(t *testing.T) {
+			req.Equal(t, tt.want, ParseUserAgent(tt.ua))
+		})
+	}
+}
+
+func TestUserAgentParserCache(t *testing.T) {
+	p := NewUserAgentParser(2, "Internal-Checker")
+	req.True(t, p.Parse("internal-checker/1.0").IsBot)
+	p.Parse("curl/8.4.0")
+	p.Parse("curl/8.4.0")
+	req.Equal(t, 2, p.Len())
+	p.Parse("Wget/1.21")
+	req.Equal(t, 2, p.Len())
+	//the least recently used one is evicted
+	_, ok := p.items["internal-checker/1.0"]
+	req.False(t, ok)
+	//long user agent is not cached
+	p.Parse("curl/" + strings.Repeat("1", maxCachedUserAgent))
+	req.Equal(t, 2, p.Len())
+}
@@ -0,0 +1,266 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 15:48:33
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 15:48:33
+ * @FilePath: /xlogrus/writer.go
+ * @Description: io.Writer adapter to redirect log package and gin writers
+ *
+ */
+
+package xlogrus
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	c "github.com/justin-ren/xlogrus/common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// max length of buffered line, it's logged even without line feed
+const maxLineLength = 64 * 1024
+
+type WriterOpt struct {
+	//level for lines without level prefix
+	LogLevel logrus.Level
+	//parse level from prefix such as [GIN-debug], [WARNING], ERROR:
+	ParsePrefix bool
+	//fields added to every line, such as source=stdlog
+	Fields logrus.Fields
+}
+
+func GetWriterOpt() *WriterOpt {
+	return &WriterOpt{
+		LogLevel:    logrus.InfoLevel,
+		ParsePrefix: true,
+	}
+}
+
+// SetLogLevel sets level for lines without level prefix.
+func (o *WriterOpt) SetLogLevel(level string) error {
+	if lvl, err := logrus.ParseLevel(level); err != nil {
+		return err
+	} else {
+		o.LogLevel = lvl
+	}
+	return nil
+}
+
+// SetParsePrefix sets whether to parse level from line prefix.
+func (o *WriterOpt) SetParsePrefix(enabled bool) error {
+	o.ParsePrefix = enabled
+	return nil
+}
+
+// SetWriterFields sets fields added to every line.
+func (o *WriterOpt) SetWriterFields(fields logrus.Fields) error {
+	o.Fields = fields
+	return nil
+}
+
+// WithParsePrefix 设置是否从行首解析日志级别
+func WithParsePrefix[
+	T any,
+	PT interface {
+		*T
+		SetParsePrefix(bool) error
+	},
+](enabled bool) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetParsePrefix(enabled)
+	})
+}
+
+// WithWriterFields 设置每行日志附加的字段
+func WithWriterFields[
+	T any,
+	PT interface {
+		*T
+		SetWriterFields(logrus.Fields) error
+	},
+](fields logrus.Fields) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetWriterFields(fields)
+	})
+}
+
+/*LogWriter
+ * @msg io.Writer to log every line by xlogrus logger, partial line is
+ *		buffered until line feed or Flush
+ */
+type LogWriter struct {
+	lg  *TLogrus
+	opt *WriterOpt
+	mu  sync.Mutex
+	buf []byte
+}
+
+func NewLogWriter(lg *TLogrus, setFunc ...c.LogOption[WriterOpt]) (*LogWriter, error) {
+	if lg == nil {
+		return nil, errors.New("logger cannot be nil")
+	}
+	opt := GetWriterOpt()
+	for _, f := range setFunc {
+		if err := f.Apply(opt); err != nil {
+			return nil, errors.Wrap(err, "failed to apply writer option")
+		}
+	}
+	return &LogWriter{lg: lg, opt: opt}, nil
+}
+
+// Write implements io.Writer
+func (w *LogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.logLine(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+	if len(w.buf) >= maxLineLength {
+		w.logLine(string(w.buf))
+		w.buf = nil
+	}
+	return len(p), nil
+}
+
+// Flush logs buffered partial line.
+func (w *LogWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		w.logLine(string(w.buf))
+		w.buf = nil
+	}
+}
+
+// Close implements io.Closer, it's same as Flush
+func (w *LogWriter) Close() error {
+	w.Flush()
+	return nil
+}
+
+func (w *LogWriter) logLine(line string) {
+	line = strings.TrimRight(line, "\r")
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	level := w.opt.LogLevel
+	if w.opt.ParsePrefix {
+		if lvl, msg, ok := parseLevelPrefix(line); ok {
+			level, line = lvl, msg
+		}
+	}
+	w.lg.WithFields(w.opt.Fields).Log(level, line)
+}
+
+/*parseLevelPrefix
+ * @msg parse level from prefix such as "[GIN-debug] [WARNING] msg", "ERROR: msg".
+ *		the most severe one is used, fatal and panic are logged as error
+ *		so writer never exits the process
+ * @param line
+ * @return: logrus.Level
+ * @return: string msg without prefix
+ * @return: bool false if no level prefix found
+ */
+func parseLevelPrefix(line string) (logrus.Level, string, bool) {
+	found := false
+	level := logrus.TraceLevel
+	for {
+		var token, rest string
+		trimmed := strings.TrimLeft(line, " ")
+		if strings.HasPrefix(trimmed, "[") {
+			end := strings.IndexByte(trimmed, ']')
+			if end < 0 {
+				break
+			}
+			token, rest = trimmed[1:end], trimmed[end+1:]
+		} else if end := strings.IndexByte(trimmed, ':'); end > 0 && !strings.ContainsAny(trimmed[:end], " \t") {
+			token, rest = trimmed[:end], trimmed[end+1:]
+		} else {
+			break
+		}
+		lvl, ok := prefixLevel(token)
+		if !ok {
+			break
+		}
+		if !found || lvl < level {
+			level = lvl
+		}
+		found = true
+		line = rest
+	}
+	return level, strings.TrimLeft(line, " "), found
+}
+
+func prefixLevel(token string) (logrus.Level, bool) {
+	switch strings.ToLower(token) {
+	case "gin-debug", "debug", "dbg":
+		return logrus.DebugLevel, true
+	case "gin", "info":
+		return logrus.InfoLevel, true
+	case "warn", "warning":
+		return logrus.WarnLevel, true
+	case "error", "err", "fatal", "panic", "gin-error":
+		return logrus.ErrorLevel, true
+	case "trace":
+		return logrus.TraceLevel, true
+	}
+	return 0, false
+}
+
+/*RedirectStd
+ * @msg redirect log package and gin.DefaultWriter/DefaultErrorWriter to lg.
+ *		log flags are cleared as logrus adds timestamp itself
+ * @param lg
+ * @return: func() to restore the original writers
+ * @return: error
+ */
+func RedirectStd(lg *TLogrus) (func(), error) {
+	stdWriter, err := NewLogWriter(lg, WithWriterFields[WriterOpt](logrus.Fields{"source": "stdlog"}))
+	if err != nil {
+		return nil, err
+	}
+	ginWriter, err := NewLogWriter(lg,
+		WithLogLevel[WriterOpt]("debug"),
+		WithWriterFields[WriterOpt](logrus.Fields{"source": "gin"}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	ginErrWriter, err := NewLogWriter(lg,
+		WithLogLevel[WriterOpt]("error"),
+		WithWriterFields[WriterOpt](logrus.Fields{"source": "gin"}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	oldOutput, oldFlags, oldPrefix := log.Writer(), log.Flags(), log.Prefix()
+	oldGinWriter, oldGinErrWriter := gin.DefaultWriter, gin.DefaultErrorWriter
+	log.SetOutput(stdWriter)
+	log.SetFlags(0)
+	log.SetPrefix("")
+	gin.DefaultWriter = ginWriter
+	gin.DefaultErrorWriter = ginErrWriter
+
+	return func() {
+		log.SetOutput(oldOutput)
+		log.SetFlags(oldFlags)
+		log.SetPrefix(oldPrefix)
+		gin.DefaultWriter = oldGinWriter
+		gin.DefaultErrorWriter = oldGinErrWriter
+		for _, w := range []*LogWriter{stdWriter, ginWriter, ginErrWriter} {
+			w.Flush()
+		}
+	}, nil
+}
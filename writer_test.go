@@ -0,0 +1,87 @@
+/*
+ * @Author: justin-ren
+ * @Date: 2026-10-19 16:20:55
+ * @LastEditors: justin-ren
+ * @LastEditTime: 2026-10-19 16:20:55
+ * @FilePath: /xlogrus/writer_test.go
+ * @Description: test io.Writer adapter and redirecting log package
+ *
+ */
+
+package xlogrus
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	lTest "github.com/sirupsen/logrus/hooks/test" //logrus tools for test
+	req "github.com/stretchr/testify/require"     //exit if failed
+)
+
+func TestLogWriter(t *testing.T) {
+	lg, opt, err := NewUserLog(WithLogPath[UserOpt](t.TempDir() + "/"))
+	req.NoError(t, err)
+	defer opt.Close()
+	lg.SetOutput(io.Discard)
+	hook := lTest.NewLocal(lg)
+
+	w, err := NewLogWriter(lg, WithLogLevel[WriterOpt]("warn"))
+	req.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		input string
+		level logrus.Level
+		msg   string
+	}{
+		{"noPrefix", "plain line\n", logrus.WarnLevel, "plain line"},
+		{"ginDebug", "[GIN-debug] GET /ping --> main.ping (3 handlers)\n", logrus.DebugLevel, "GET /ping --> main.ping (3 handlers)"},
+		{"mostSevere", "[GIN-debug] [WARNING] Running in \"debug\" mode\n", logrus.WarnLevel, "Running in \"debug\" mode"},
+		{"colon", "ERROR: connection refused\r\n", logrus.ErrorLevel, "connection refused"},
+		{"notLevel", "http: TLS handshake error\n", logrus.WarnLevel, "http: TLS handshake error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := fmt.Fprint(w, tt.input)
+			req.NoError(t, err)
+			req.Equal(t, tt.level, hook.LastEntry().Level)
+			req.Equal(t, tt.msg, hook.LastEntry().Message)
+		})
+	}
+
+	//partial line is buffered until flush
+	hook.Reset()
+	_, _ = w.Write([]byte("partial"))
+	req.Nil(t, hook.LastEntry())
+	req.NoError(t, w.Close())
+	req.Equal(t, "partial", hook.LastEntry().Message)
+}
+
+func TestRedirectStd(t *testing.T) {
+	lg, opt, err := NewUserLog(WithLogPath[UserOpt](t.TempDir() + "/"))
+	req.NoError(t, err)
+	defer opt.Close()
+	lg.SetOutput(io.Discard)
+	hook := lTest.NewLocal(lg)
+
+	restore, err := RedirectStd(lg)
+	req.NoError(t, err)
+	log.Printf("from %s", "stdlog")
+	req.Equal(t, "from stdlog", hook.LastEntry().Message)
+	req.Equal(t, "stdlog", hook.LastEntry().Data["source"])
+
+	fmt.Fprintln(gin.DefaultErrorWriter, "[GIN-debug] [ERROR] listen failed")
+	req.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+	restore()
+
+	hook.Reset()
+	log.SetOutput(io.Discard)
+	log.Print("not redirected")
+	req.Nil(t, hook.LastEntry())
+	log.SetOutput(os.Stderr)
+}
@@ -10,6 +10,8 @@
 package xlogrus
 
 import (
+	"time"
+
 	c "github.com/justin-ren/xlogrus/common"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm/logger"
@@ -18,6 +20,13 @@ import (
 type TLogrus = logrus.Logger
 type TGormLog = logger.LogLevel
 
+// Metrics counts log entries and bytes written, it's also a http.Handler for prometheus
+type Metrics = c.Metrics
+
+func NewMetrics() *Metrics {
+	return c.NewMetrics()
+}
+
 // type TGinHandleFunc = gin.HandlerFunc
 
 // WithFileNamePrefix sets the filename prefix.
@@ -158,3 +167,94 @@ func WithErrLogSuffix[
 		return PT(t).SetErrLogSuffix(suffix)
 	})
 }
+
+// WithFallbackPath 设置日志写入失败时的备用路径
+func WithFallbackPath[
+	T any,
+	PT interface {
+		*T
+		SetFallbackPath(string) error
+	},
+](path string) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetFallbackPath(path)
+	})
+}
+
+// WithFallbackStderr 设置日志文件全部写入失败时是否输出到stderr
+func WithFallbackStderr[
+	T any,
+	PT interface {
+		*T
+		SetFallbackStderr(bool) error
+	},
+](enabled bool) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetFallbackStderr(enabled)
+	})
+}
+
+// WithRetryInterval 设置写入失败后重试主路径的间隔
+func WithRetryInterval[
+	T any,
+	PT interface {
+		*T
+		SetRetryInterval(time.Duration) error
+	},
+](interval time.Duration) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetRetryInterval(interval)
+	})
+}
+
+// WithFileFormatter 设置日志文件的格式, 不影响标准输出和error.log
+func WithFileFormatter[
+	T any,
+	PT interface {
+		*T
+		SetFileFormatter(logrus.Formatter) error
+	},
+](f logrus.Formatter) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetFileFormatter(f)
+	})
+}
+
+// WithLoggerName 设置日志名称，用于metrics标签
+func WithLoggerName[
+	T any,
+	PT interface {
+		*T
+		SetLoggerName(string) error
+	},
+](name string) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetLoggerName(name)
+	})
+}
+
+// WithMetrics 设置日志数量和写入字节数的统计
+func WithMetrics[
+	T any,
+	PT interface {
+		*T
+		SetMetrics(*Metrics) error
+	},
+](m *Metrics) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetMetrics(m)
+	})
+}
+
+// WithHooks 添加额外的hook，例如OTLPExporter
+func WithHooks[
+	T any,
+	PT interface {
+		*T
+		SetHooks(...logrus.Hook) error
+	},
+](hooks ...logrus.Hook) c.LogOption[T] {
+	return c.NewLogOptionFunc(func(t *T) error {
+		return PT(t).SetHooks(hooks...)
+	})
+}